@@ -4,35 +4,47 @@ package main
 import (
 	"flag"
 	"fmt"
-	"io/ioutil"
+	"os"
+	"path/filepath"
 
 	"github.com/tfkhsr/jsonschema"
 	"github.com/tfkhsr/jsonschema/golang"
+	"github.com/tfkhsr/jsonschema/lint"
 )
 
 func main() {
 	file := flag.String("file", "schema.json", "json schema file to load")
 	pack := flag.String("package", "main", "name for generated package")
 	gen := flag.String("generator", "go", "generator to use")
+	allErrors := flag.Bool("all-errors", false, "generate validations reporting all errors instead of the first")
+	strict := flag.Bool("strict", false, "fail on unknown keywords")
+	extensions := flag.Bool("allow-extensions", false, "allow x- prefixed keywords in strict mode")
+	lintDoc := flag.Bool("lint", false, "print lint diagnostics of the schema to stderr")
+	validate := flag.Bool("validate-schema", false, "validate the schema against the meta-schema of its draft")
 	flag.Parse()
 
-	// read schema
-	buf, err := ioutil.ReadFile(*file)
+	// parse schema including referenced files relative to it
+	dir, name := filepath.Split(*file)
+	if dir == "" {
+		dir = "."
+	}
+	opts := jsonschema.ParseOptions{Strict: *strict, AllowExtensions: *extensions, ValidateDocument: *validate}
+	idx, err := jsonschema.ParseWithLoaderOptions(name, jsonschema.FSLoader{FS: os.DirFS(dir)}, opts)
 	if err != nil {
 		panic(err)
 	}
 
-	// parse schema
-	idx, err := jsonschema.Parse(buf)
-	if err != nil {
-		panic(err)
+	if *lintDoc {
+		for _, d := range lint.Lint(idx) {
+			fmt.Fprintln(os.Stderr, d)
+		}
 	}
 
 	// generate src
 	var src []byte
 	switch *gen {
 	case "go":
-		src, err = golang.PackageSrc(idx, *pack)
+		src, err = golang.PackageSrcWithOptions(idx, *pack, golang.Options{AllErrors: *allErrors})
 	default:
 		err = fmt.Errorf("unknown generator: %s", *gen)
 	}
@@ -0,0 +1,95 @@
+package jsonschema
+
+import (
+	"strings"
+)
+
+// Draft is a version of the JSON Schema specification selecting the semantics of a schema.
+// Drafts differ in the keywords describing the items of arrays:
+// draft-07 and 2019-09 describe tuples with a list of items followed by additionalItems,
+// 2020-12 with prefixItems followed by items. 2019-09 and 2020-12 add unevaluatedItems and unevaluatedProperties,
+// 2019-09 adds $recursiveRef, which 2020-12 replaces with $dynamicRef.
+// Since 2019-09 $ref applies next to the other keywords of a schema instead of replacing it.
+type Draft int
+
+const (
+	// DraftAny applies to schemas without a known $schema and honors the keywords of all drafts
+	DraftAny Draft = iota
+
+	// Draft7 as identified by http://json-schema.org/draft-07/schema#
+	Draft7
+
+	// Draft201909 as identified by https://json-schema.org/draft/2019-09/schema
+	Draft201909
+
+	// Draft202012 as identified by https://json-schema.org/draft/2020-12/schema
+	Draft202012
+)
+
+// meta-schema URIs without scheme and empty fragment by draft
+var draftURIs = map[string]Draft{
+	"json-schema.org/draft-07/schema":      Draft7,
+	"json-schema.org/draft/2019-09/schema": Draft201909,
+	"json-schema.org/draft/2020-12/schema": Draft202012,
+}
+
+func (d Draft) String() string {
+	switch d {
+	case Draft7:
+		return "draft-07"
+	case Draft201909:
+		return "2019-09"
+	case Draft202012:
+		return "2020-12"
+	}
+	return "any"
+}
+
+// returns the draft identified by a $schema URI, DraftAny for unknown URIs
+func draftOf(uri string) Draft {
+	u := strings.TrimSuffix(uri, "#")
+	u = strings.TrimPrefix(u, "http://")
+	u = strings.TrimPrefix(u, "https://")
+	return draftURIs[u]
+}
+
+// returns true if $ref applies next to the other keywords of a schema as in 2019-09 and 2020-12,
+// otherwise the referenced schema replaces the schema with $ref and its other keywords are ignored
+func (d Draft) appliesRefInPlace() bool {
+	return d == Draft201909 || d == Draft202012
+}
+
+// returns true if the draft defines a keyword, keywords a draft does not define are kept but ignored
+func (d Draft) defines(keyword string) bool {
+	switch keyword {
+	case "prefixItems", "$dynamicRef", "$dynamicAnchor":
+		return d == DraftAny || d == Draft202012
+	case "$recursiveRef", "$recursiveAnchor":
+		return d == DraftAny || d == Draft201909
+	case "unevaluatedItems", "unevaluatedProperties":
+		return d != Draft7
+	case "additionalItems":
+		return d != Draft202012
+	}
+	return true
+}
+
+// TupleItems returns the schemas of the leading items of a tuple and the schema of all other items,
+// from prefixItems and items or from a list of items and additionalItems as defined by the draft of the schema
+func (s *Schema) TupleItems() ([]*Schema, *Schema) {
+	if len(s.PrefixItems) > 0 && s.Draft.defines("prefixItems") {
+		return s.PrefixItems, s.Items
+	}
+	if len(s.ItemsList) > 0 && s.Draft != Draft202012 {
+		return s.ItemsList, s.AdditionalItems
+	}
+	return nil, s.Items
+}
+
+// returns unevaluatedItems and unevaluatedProperties if the draft of the schema defines them
+func (s *Schema) unevaluated() (*Schema, *Schema) {
+	if !s.Draft.defines("unevaluatedItems") {
+		return nil, nil
+	}
+	return s.UnevaluatedItems, s.UnevaluatedProperties
+}
@@ -0,0 +1,83 @@
+package jsonschema
+
+import (
+	"fmt"
+	"strings"
+)
+
+// A ValidationError describes an instance violating a schema keyword
+type ValidationError struct {
+	// JSON pointer to the invalid part of the instance, empty for the instance root
+	InstancePointer string
+
+	// JSON pointer of the violated schema, see Schema.Pointer
+	SchemaPointer string
+
+	// Violated keyword, e.g. required
+	Keyword string
+
+	// Human readable description of the violation
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("jsonschema: invalid instance at #%v: %v", e.InstancePointer, e.Message)
+}
+
+// ValidationErrors collects multiple validation errors of a single instance
+type ValidationErrors []*ValidationError
+
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, err := range e {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// creates a validation error for a keyword of a schema
+func newValidationError(s *Schema, keyword string, ptr string, format string, args ...interface{}) *ValidationError {
+	return &ValidationError{
+		InstancePointer: ptr,
+		SchemaPointer:   s.Pointer,
+		Keyword:         keyword,
+		Message:         fmt.Sprintf(format, args...),
+	}
+}
+
+// A KeywordError describes an unknown keyword of a schema found by strict parsing, see ParseOptions
+type KeywordError struct {
+	// JSON pointer of the unknown keyword, see Pointer
+	Pointer string
+
+	// Position of the schema with the unknown keyword
+	Position Position
+
+	// Unknown keyword, e.g. requried
+	Keyword string
+
+	// Closest known keyword, e.g. required, empty if no keyword is close
+	Suggestion string
+}
+
+func (e *KeywordError) Error() string {
+	at := ""
+	if e.Position.IsValid() {
+		at = e.Position.String() + ": "
+	}
+	if e.Suggestion != "" {
+		return fmt.Sprintf("jsonschema: %vunknown keyword %v at %v, did you mean %v?", at, e.Keyword, e.Pointer, e.Suggestion)
+	}
+	return fmt.Sprintf("jsonschema: %vunknown keyword %v at %v", at, e.Keyword, e.Pointer)
+}
+
+// KeywordErrors collects all unknown keywords of a schema document
+type KeywordErrors []*KeywordError
+
+func (e KeywordErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, err := range e {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
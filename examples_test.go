@@ -3,7 +3,6 @@ package jsonschema
 import (
 	"encoding/json"
 	"fmt"
-	"sort"
 )
 
 // Parse a schema into an Index of Schemas
@@ -50,17 +49,13 @@ func ExampleParse() {
 		panic(err)
 	}
 
-	// sort pointers by name
-	pointers := make([]string, 0)
-	for pointer, _ := range *idx {
-		pointers = append(pointers, pointer)
-	}
-	sort.Strings(pointers)
-
-	// print pointer and Go friendly name
-	for _, pointer := range pointers {
-		fmt.Printf("%s : %s\n", pointer, (*idx)[pointer].Name)
-	}
+	// print pointer and Go friendly name of named schemas, Range visits the pointers sorted by alphabet
+	idx.Range(func(pointer string, s *Schema) bool {
+		if s.Name != "" {
+			fmt.Printf("%s : %s\n", pointer, s.Name)
+		}
+		return true
+	})
 	// Output:
 	// #/definitions/role : Role
 	// #/definitions/role/properties/name : Name
@@ -117,7 +112,8 @@ func ExampleSchema_NewInstance() {
 	}
 
 	// create go instance
-	inst, err := (*idx)["#/definitions/user"].NewInstance(idx)
+	user, _ := idx.Get("#/definitions/user")
+	inst, err := user.NewInstance(idx)
 	if err != nil {
 		panic(err)
 	}
@@ -140,3 +136,38 @@ func ExampleSchema_NewInstance() {
 	//   ]
 	// }
 }
+
+// Validate a JSON document against a schema
+func ExampleIndex_ValidateJSON() {
+	schema := `
+{
+	"definitions": {
+		"user": {
+			"type": "object",
+			"properties": {
+				"id": {
+					"type": "string"
+				},
+				"name": {
+					"type": "string"
+				}
+			},
+			"required": ["id"]
+		}
+	}
+}
+`
+
+	// parse into index
+	idx, err := Parse([]byte(schema))
+	if err != nil {
+		panic(err)
+	}
+
+	// validate documents
+	fmt.Println(idx.ValidateJSON("#/definitions/user", []byte(`{"id": "1", "name": "John Snow"}`)))
+	fmt.Println(idx.ValidateJSON("#/definitions/user", []byte(`{"name": "John Snow"}`)))
+	// Output:
+	// <nil>
+	// jsonschema: invalid instance at #/id: missing property id
+}
@@ -190,5 +190,849 @@ const (
 		}
 	}
 }
+`
+
+	// Schema with validations: enum, const
+	TestSchemaEnumValidation = `
+{
+	"definitions": {
+		"movie": {
+			"type": "object",
+			"required": ["id"],
+			"properties": {
+				"id": {
+					"type": "string"
+				},
+				"status": {
+					"type": "string",
+					"enum": ["released", "in-production", "planned"]
+				},
+				"rating": {
+					"$ref": "#/definitions/rating"
+				},
+				"kind": {
+					"const": "movie"
+				}
+			}
+		},
+		"rating": {
+			"type": "integer",
+			"enum": [1, 2, 3, 4, 5]
+		}
+	}
+}
+`
+
+	// Schema with validations: allOf, anyOf, oneOf, not
+	TestSchemaCompositionValidation = `
+{
+	"definitions": {
+		"animal": {
+			"type": "object",
+			"required": ["name"],
+			"properties": {
+				"name": {
+					"type": "string"
+				}
+			}
+		},
+		"cat": {
+			"type": "object",
+			"allOf": [
+				{
+					"$ref": "#/definitions/animal"
+				},
+				{
+					"type": "object",
+					"required": ["lives"],
+					"properties": {
+						"lives": {
+							"type": "integer"
+						}
+					}
+				}
+			],
+			"properties": {
+				"indoor": {
+					"type": "boolean"
+				}
+			}
+		},
+		"dog": {
+			"type": "object",
+			"allOf": [
+				{
+					"$ref": "#/definitions/animal"
+				}
+			],
+			"required": ["breed"],
+			"properties": {
+				"breed": {
+					"type": "string"
+				}
+			}
+		},
+		"pet": {
+			"oneOf": [
+				{
+					"$ref": "#/definitions/cat"
+				},
+				{
+					"$ref": "#/definitions/dog"
+				}
+			]
+		},
+		"tag": {
+			"anyOf": [
+				{
+					"type": "string"
+				},
+				{
+					"type": "integer"
+				}
+			]
+		},
+		"owner": {
+			"type": "object",
+			"properties": {
+				"name": {
+					"type": "string",
+					"not": {
+						"enum": ["nobody"]
+					}
+				},
+				"pet": {
+					"$ref": "#/definitions/pet"
+				},
+				"tags": {
+					"type": "array",
+					"items": {
+						"$ref": "#/definitions/tag"
+					}
+				}
+			}
+		}
+	}
+}
+`
+
+	// Schema with validations: additionalProperties, patternProperties
+	TestSchemaAdditionalPropertiesValidation = `
+{
+	"definitions": {
+		"movie": {
+			"type": "object",
+			"required": ["id"],
+			"properties": {
+				"id": {
+					"type": "string"
+				},
+				"labels": {
+					"$ref": "#/definitions/labels"
+				},
+				"actor": {
+					"$ref": "#/definitions/actor"
+				}
+			},
+			"additionalProperties": false
+		},
+		"labels": {
+			"type": "object",
+			"additionalProperties": {
+				"type": "string"
+			}
+		},
+		"actor": {
+			"type": "object",
+			"properties": {
+				"name": {
+					"type": "string"
+				}
+			},
+			"additionalProperties": {
+				"$ref": "#/definitions/location"
+			}
+		},
+		"location": {
+			"type": "object",
+			"required": ["name"],
+			"properties": {
+				"name": {
+					"type": "string"
+				}
+			}
+		},
+		"scores": {
+			"type": "object",
+			"patternProperties": {
+				"^[a-z]+$": {
+					"type": "integer"
+				}
+			},
+			"additionalProperties": false
+		}
+	}
+}
+`
+
+	// Schema with validations: multipleOf, maximum, exclusiveMaximum, minimum, exclusiveMinimum, maxLength, minLength, pattern
+	TestSchemaConstraintValidation = `
+{
+	"definitions": {
+		"movie": {
+			"type": "object",
+			"required": ["id"],
+			"properties": {
+				"id": {
+					"type": "string",
+					"pattern": "^[a-z0-9-]+$"
+				},
+				"title": {
+					"type": "string",
+					"minLength": 1,
+					"maxLength": 10
+				},
+				"year": {
+					"type": "integer",
+					"minimum": 1900,
+					"maximum": 2100
+				},
+				"rating": {
+					"type": "number",
+					"exclusiveMinimum": 0,
+					"exclusiveMaximum": 10,
+					"multipleOf": 0.1
+				},
+				"runtime": {
+					"$ref": "#/definitions/minutes"
+				},
+				"budget": {
+					"type": "integer",
+					"maximum": 1.5e6
+				},
+				"score": {
+					"type": "integer",
+					"maximum": 99.5
+				}
+			}
+		},
+		"minutes": {
+			"type": "integer",
+			"exclusiveMinimum": 0,
+			"multipleOf": 5
+		}
+	}
+}
+`
+
+	// Schema with validations: maxItems, minItems, uniqueItems, contains, maxContains, minContains
+	TestSchemaArrayValidation = `
+{
+	"definitions": {
+		"movie": {
+			"type": "object",
+			"properties": {
+				"roles": {
+					"$ref": "#/definitions/roles"
+				},
+				"tags": {
+					"$ref": "#/definitions/tags"
+				},
+				"scores": {
+					"$ref": "#/definitions/scores"
+				},
+				"cast": {
+					"$ref": "#/definitions/cast"
+				}
+			}
+		},
+		"role": {
+			"type": "object",
+			"required": ["name"],
+			"properties": {
+				"name": {
+					"type": "string"
+				},
+				"permissions": {
+					"type": "string"
+				}
+			}
+		},
+		"admin": {
+			"type": "object",
+			"required": ["name", "permissions"],
+			"properties": {
+				"name": {
+					"type": "string"
+				},
+				"permissions": {
+					"enum": ["all"]
+				}
+			}
+		},
+		"roles": {
+			"type": "array",
+			"items": {
+				"$ref": "#/definitions/role"
+			},
+			"minItems": 1,
+			"maxItems": 3,
+			"uniqueItems": true,
+			"contains": {
+				"$ref": "#/definitions/admin"
+			}
+		},
+		"tags": {
+			"type": "array",
+			"items": {
+				"type": "string"
+			},
+			"contains": {
+				"const": "new"
+			},
+			"minContains": 0,
+			"maxContains": 1
+		},
+		"scores": {
+			"type": "array",
+			"items": {
+				"type": "integer"
+			},
+			"minItems": 2,
+			"uniqueItems": true
+		},
+		"cast": {
+			"type": "array",
+			"items": {
+				"$ref": "#/definitions/role"
+			},
+			"contains": {
+				"$ref": "#/definitions/role"
+			},
+			"minContains": 2
+		}
+	}
+}
+`
+
+	// Schema with validations: maxProperties, minProperties, propertyNames, dependentRequired, dependentSchemas, dependencies
+	TestSchemaObjectValidation = `
+{
+	"definitions": {
+		"movie": {
+			"type": "object",
+			"properties": {
+				"id": {
+					"type": "string"
+				},
+				"title": {
+					"type": "string"
+				},
+				"sequel": {
+					"type": "string"
+				},
+				"prequel": {
+					"type": "string"
+				}
+			},
+			"minProperties": 1,
+			"maxProperties": 3,
+			"dependentRequired": {
+				"sequel": ["title"]
+			},
+			"dependentSchemas": {
+				"prequel": {
+					"required": ["id"]
+				}
+			}
+		},
+		"labels": {
+			"type": "object",
+			"additionalProperties": {
+				"type": "string"
+			},
+			"propertyNames": {
+				"pattern": "^[a-z]+$",
+				"maxLength": 8
+			},
+			"maxProperties": 2,
+			"dependentRequired": {
+				"genre": ["year"]
+			}
+		},
+		"series": {
+			"type": "object",
+			"properties": {
+				"season": {
+					"type": "integer"
+				},
+				"episode": {
+					"type": "integer"
+				},
+				"network": {
+					"type": "string"
+				}
+			},
+			"dependencies": {
+				"episode": ["season"],
+				"network": {
+					"required": ["season"],
+					"properties": {
+						"season": {
+							"minimum": 1
+						}
+					}
+				}
+			}
+		}
+	}
+}
+`
+
+	// Schema with validations: if, then, else
+	TestSchemaConditionalValidation = `
+{
+	"definitions": {
+		"payment": {
+			"type": "object",
+			"properties": {
+				"kind": {
+					"enum": ["card", "cash"]
+				},
+				"cardNumber": {
+					"type": "string"
+				},
+				"amount": {
+					"type": "number"
+				}
+			},
+			"if": {
+				"required": ["kind"],
+				"properties": {
+					"kind": {
+						"const": "card"
+					}
+				}
+			},
+			"then": {
+				"required": ["cardNumber"],
+				"properties": {
+					"cardNumber": {
+						"pattern": "^[0-9]{16}$"
+					}
+				}
+			},
+			"else": {
+				"properties": {
+					"amount": {
+						"maximum": 1000
+					}
+				}
+			}
+		},
+		"address": {
+			"type": "object",
+			"properties": {
+				"country": {
+					"type": "string"
+				},
+				"postalCode": {
+					"type": "string"
+				}
+			},
+			"if": {
+				"properties": {
+					"country": {
+						"const": "US"
+					}
+				}
+			},
+			"then": {
+				"properties": {
+					"postalCode": {
+						"pattern": "^[0-9]{5}$"
+					}
+				}
+			}
+		}
+	}
+}
+`
+
+	// Schema with validations: nullable types and several types
+	TestSchemaNullableValidation = `
+{
+	"definitions": {
+		"profile": {
+			"type": "object",
+			"properties": {
+				"nickname": {
+					"type": ["string", "null"],
+					"maxLength": 5
+				},
+				"age": {
+					"type": ["integer", "null"],
+					"minimum": 0
+				},
+				"address": {
+					"$ref": "#/definitions/address"
+				},
+				"score": {
+					"$ref": "#/definitions/score"
+				}
+			},
+			"required": ["nickname"]
+		},
+		"address": {
+			"type": ["object", "null"],
+			"properties": {
+				"city": {
+					"type": "string"
+				}
+			},
+			"required": ["city"]
+		},
+		"score": {
+			"type": ["string", "integer"],
+			"minLength": 1,
+			"maximum": 100
+		}
+	}
+}
+`
+
+	// Schema referencing definitions of TestSchemaExternalRefCommon as common.json
+	TestSchemaExternalRef = `
+{
+	"definitions": {
+		"person": {
+			"type": "object",
+			"properties": {
+				"name": {
+					"type": "string"
+				},
+				"address": {
+					"$ref": "common.json#/definitions/address"
+				}
+			},
+			"required": ["name"]
+		},
+		"country": {
+			"type": "string",
+			"minLength": 2,
+			"maxLength": 2
+		}
+	}
+}
+`
+
+	// Schema referenced by TestSchemaExternalRef, referencing it back as schema.json
+	TestSchemaExternalRefCommon = `
+{
+	"definitions": {
+		"address": {
+			"type": "object",
+			"properties": {
+				"city": {
+					"$ref": "#/definitions/city"
+				},
+				"country": {
+					"$ref": "schema.json#/definitions/country"
+				}
+			},
+			"required": ["city"]
+		},
+		"city": {
+			"type": "string"
+		}
+	}
+}
+`
+
+	// Schema with references by $id and $anchor
+	TestSchemaIDAndAnchor = `
+{
+	"$id": "http://example.com/schemas/root.json",
+	"definitions": {
+		"person": {
+			"type": "object",
+			"properties": {
+				"name": {
+					"$ref": "#name"
+				},
+				"address": {
+					"$ref": "address.json"
+				},
+				"city": {
+					"$ref": "address.json#/properties/city"
+				},
+				"zip": {
+					"$ref": "address.json#zip"
+				},
+				"age": {
+					"$ref": "#age"
+				}
+			},
+			"required": ["name"]
+		},
+		"name": {
+			"$anchor": "name",
+			"type": "string",
+			"minLength": 1
+		},
+		"address": {
+			"$id": "address.json",
+			"type": "object",
+			"properties": {
+				"city": {
+					"type": "string"
+				},
+				"zip": {
+					"$anchor": "zip",
+					"type": "string",
+					"pattern": "^[0-9]{5}$"
+				}
+			},
+			"required": ["city"]
+		},
+		"age": {
+			"$id": "#age",
+			"type": "integer",
+			"minimum": 0
+		}
+	}
+}
+`
+
+	// Schema of draft 2020-12 with $defs, prefixItems and unevaluated keywords
+	TestSchemaDraft202012 = `
+{
+	"$schema": "https://json-schema.org/draft/2020-12/schema",
+	"$defs": {
+		"point": {
+			"type": "array",
+			"prefixItems": [
+				{ "type": "number" },
+				{ "type": "number" }
+			],
+			"items": false
+		},
+		"measurement": {
+			"type": "array",
+			"prefixItems": [
+				{ "type": "string" }
+			],
+			"unevaluatedItems": {
+				"type": "number"
+			}
+		},
+		"person": {
+			"type": "object",
+			"properties": {
+				"name": {
+					"type": "string"
+				}
+			},
+			"allOf": [
+				{
+					"properties": {
+						"age": {
+							"type": "integer"
+						}
+					}
+				}
+			],
+			"unevaluatedProperties": false
+		}
+	}
+}
+`
+
+	// Schema of draft-07 with tuple items, ignoring prefixItems
+	TestSchemaDraft7 = `
+{
+	"$schema": "http://json-schema.org/draft-07/schema#",
+	"definitions": {
+		"point": {
+			"type": "array",
+			"items": [
+				{ "type": "number" },
+				{ "type": "number" }
+			],
+			"additionalItems": false
+		},
+		"names": {
+			"type": "array",
+			"prefixItems": [
+				{ "type": "number" }
+			],
+			"items": {
+				"type": "string"
+			}
+		}
+	}
+}
+`
+
+	// Schema with names requiring escaping in JSON pointers
+	TestSchemaEscapedPointers = `
+{
+	"definitions": {
+		"a/b": {
+			"type": "string"
+		},
+		"x~y": {
+			"type": "integer"
+		},
+		"file": {
+			"type": "object",
+			"properties": {
+				"mime/type": {
+					"$ref": "#/definitions/a~1b"
+				},
+				"size~bytes": {
+					"$ref": "#/definitions/x~0y"
+				},
+				"display name": {
+					"$ref": "#/definitions/a~1b"
+				}
+			},
+			"required": ["mime/type"]
+		}
+	}
+}
+`
+
+	// Schema with recursive types
+	TestSchemaRecursive = `
+{
+	"definitions": {
+		"node": {
+			"type": "object",
+			"properties": {
+				"name": {
+					"type": "string",
+					"minLength": 1
+				},
+				"children": {
+					"type": "array",
+					"items": {
+						"$ref": "#/definitions/node"
+					}
+				},
+				"links": {
+					"type": "object",
+					"additionalProperties": {
+						"$ref": "#/definitions/node"
+					}
+				}
+			},
+			"required": ["name"]
+		},
+		"category": {
+			"type": ["object", "null"],
+			"properties": {
+				"name": {
+					"type": "string"
+				},
+				"parent": {
+					"$ref": "#/definitions/category"
+				}
+			}
+		},
+		"tree": {
+			"type": "object",
+			"properties": {
+				"root": {
+					"$ref": "#/definitions/node"
+				},
+				"category": {
+					"$ref": "#/definitions/category"
+				}
+			}
+		}
+	}
+}
+`
+
+	// Schema without types, inferred from keywords or allowing any value
+	TestSchemaTypeInference = `
+{
+	"definitions": {
+		"document": {
+			"properties": {
+				"id": {
+					"type": "string"
+				},
+				"meta": {},
+				"extra": true,
+				"kind": {
+					"enum": ["draft", 1]
+				},
+				"tags": {
+					"items": {
+						"type": "string"
+					}
+				},
+				"author": {
+					"properties": {
+						"name": {
+							"type": "string"
+						}
+					},
+					"required": ["name"]
+				}
+			},
+			"required": ["id", "meta"]
+		}
+	}
+}
+`
+
+	// Schema with boolean schemas
+	TestSchemaBooleanSchemas = `
+{
+	"definitions": {
+		"always": true,
+		"never": false,
+		"movie": {
+			"type": "object",
+			"properties": {
+				"id": {
+					"type": "string"
+				},
+				"legacy": false,
+				"meta": true,
+				"rating": {
+					"$ref": "#/definitions/always"
+				},
+				"sequel": {
+					"$ref": "#/definitions/never"
+				},
+				"tags": {
+					"type": "array",
+					"items": true
+				},
+				"credits": {
+					"type": "array",
+					"items": false
+				},
+				"pair": {
+					"type": "array",
+					"items": [
+						{ "type": "string" },
+						true
+					],
+					"additionalItems": false
+				},
+				"labels": {
+					"type": "object",
+					"additionalProperties": true
+				}
+			},
+			"required": ["id"]
+		}
+	}
+}
 `
 )
@@ -7,12 +7,29 @@ import (
 
 func TestFixtureUnmarshal(t *testing.T) {
 	fs := map[string]string{
-		"TestSchemaWithDefinitions":       TestSchemaWithDefinitions,
-		"TestSchemaDirect":                TestSchemaDirect,
-		"TestSchemaWithNestedDefinitions": TestSchemaWithNestedDefinitions,
-		"TestSchemaPrimitiveTypes":        TestSchemaPrimitiveTypes,
-		"TestSchemaRequiredValidation":    TestSchemaRequiredValidation,
-		"TestSchemaWithArrayOfObjects":    TestSchemaWithArrayOfObjects,
+		"TestSchemaWithDefinitions":                TestSchemaWithDefinitions,
+		"TestSchemaDirect":                         TestSchemaDirect,
+		"TestSchemaWithNestedDefinitions":          TestSchemaWithNestedDefinitions,
+		"TestSchemaPrimitiveTypes":                 TestSchemaPrimitiveTypes,
+		"TestSchemaRequiredValidation":             TestSchemaRequiredValidation,
+		"TestSchemaWithArrayOfObjects":             TestSchemaWithArrayOfObjects,
+		"TestSchemaEnumValidation":                 TestSchemaEnumValidation,
+		"TestSchemaCompositionValidation":          TestSchemaCompositionValidation,
+		"TestSchemaAdditionalPropertiesValidation": TestSchemaAdditionalPropertiesValidation,
+		"TestSchemaConstraintValidation":           TestSchemaConstraintValidation,
+		"TestSchemaArrayValidation":                TestSchemaArrayValidation,
+		"TestSchemaObjectValidation":               TestSchemaObjectValidation,
+		"TestSchemaConditionalValidation":          TestSchemaConditionalValidation,
+		"TestSchemaNullableValidation":             TestSchemaNullableValidation,
+		"TestSchemaExternalRef":                    TestSchemaExternalRef,
+		"TestSchemaExternalRefCommon":              TestSchemaExternalRefCommon,
+		"TestSchemaIDAndAnchor":                    TestSchemaIDAndAnchor,
+		"TestSchemaDraft202012":                    TestSchemaDraft202012,
+		"TestSchemaDraft7":                         TestSchemaDraft7,
+		"TestSchemaEscapedPointers":                TestSchemaEscapedPointers,
+		"TestSchemaRecursive":                      TestSchemaRecursive,
+		"TestSchemaTypeInference":                  TestSchemaTypeInference,
+		"TestSchemaBooleanSchemas":                 TestSchemaBooleanSchemas,
 	}
 	for k, v := range fs {
 		var o interface{}
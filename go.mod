@@ -0,0 +1,3 @@
+module github.com/tfkhsr/jsonschema
+
+go 1.24
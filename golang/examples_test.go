@@ -60,7 +60,7 @@ func ExamplePackageSrc() {
 	//package main
 	//
 	//import (
-	//	"errors"
+	//	"strconv"
 	//)
 	//
 	//type Role struct {
@@ -77,30 +77,89 @@ func ExamplePackageSrc() {
 	//
 	//func (t *Role) Validate() error {
 	//	if t.Name == nil {
-	//		return errors.New("invalid role: missing name")
+	//		return &ValidationError{
+	//			InstancePointer: "/name",
+	//			SchemaPointer:   "#/definitions/role",
+	//			Keyword:         "required",
+	//			Message:         "invalid role: missing name",
+	//		}
 	//	}
 	//
 	//	return nil
 	//}
 	//
 	//func (t *Roles) Validate() error {
-	//	for _, a := range *t {
-	//		err := a.Validate()
+	//	for i := range *t {
+	//		err := (*t)[i].Validate()
 	//		if err != nil {
-	//			return err
+	//			return prefixValidationError(err, "/"+strconv.Itoa(i))
 	//		}
 	//	}
 	//	return nil
 	//}
 	//
 	//func (t *User) Validate() error {
-	//	err := t.Roles.Validate()
-	//	if err != nil {
-	//		return err
+	//	if t.Roles != nil {
+	//		err := t.Roles.Validate()
+	//		if err != nil {
+	//			return prefixValidationError(err, "/roles")
+	//		}
 	//	}
 	//	return nil
 	//}
 	//
+	//// ValidationError describes a value violating a schema keyword
+	//type ValidationError struct {
+	//	// JSON pointer to the invalid value, empty for the validated value itself
+	//	InstancePointer string
+	//
+	//	// JSON pointer of the violated schema
+	//	SchemaPointer string
+	//
+	//	// Violated keyword, e.g. required
+	//	Keyword string
+	//
+	//	// Human readable description of the violation
+	//	Message string
+	//}
+	//
+	//func (e *ValidationError) Error() string {
+	//	return e.Message
+	//}
+	//
+	//// ValidationErrors collects multiple validation errors of a single value
+	//type ValidationErrors []*ValidationError
+	//
+	//func (e ValidationErrors) Error() string {
+	//	msg := ""
+	//	for i, err := range e {
+	//		if i > 0 {
+	//			msg += "; "
+	//		}
+	//		msg += err.Error()
+	//	}
+	//	return msg
+	//}
+	//
+	//// prefixes the instance pointers of validation errors with the pointer of the validated value
+	//func prefixValidationError(err error, prefix string) error {
+	//	switch e := err.(type) {
+	//	case *ValidationError:
+	//		p := *e
+	//		p.InstancePointer = prefix + p.InstancePointer
+	//		return &p
+	//	case ValidationErrors:
+	//		ps := make(ValidationErrors, 0, len(e))
+	//		for _, ve := range e {
+	//			p := *ve
+	//			p.InstancePointer = prefix + p.InstancePointer
+	//			ps = append(ps, &p)
+	//		}
+	//		return ps
+	//	}
+	//	return err
+	//}
+	//
 	//func newString(s string) *string {
 	//	return &s
 	//}
@@ -184,30 +243,89 @@ func ExampleSrc() {
 	//
 	//func (t *Role) Validate() error {
 	//	if t.Name == nil {
-	//		return errors.New("invalid role: missing name")
+	//		return &ValidationError{
+	//			InstancePointer: "/name",
+	//			SchemaPointer:   "#/definitions/role",
+	//			Keyword:         "required",
+	//			Message:         "invalid role: missing name",
+	//		}
 	//	}
 	//
 	//	return nil
 	//}
 	//
 	//func (t *Roles) Validate() error {
-	//	for _, a := range *t {
-	//		err := a.Validate()
+	//	for i := range *t {
+	//		err := (*t)[i].Validate()
 	//		if err != nil {
-	//			return err
+	//			return prefixValidationError(err, "/"+strconv.Itoa(i))
 	//		}
 	//	}
 	//	return nil
 	//}
 	//
 	//func (t *User) Validate() error {
-	//	err := t.Roles.Validate()
-	//	if err != nil {
-	//		return err
+	//	if t.Roles != nil {
+	//		err := t.Roles.Validate()
+	//		if err != nil {
+	//			return prefixValidationError(err, "/roles")
+	//		}
 	//	}
 	//	return nil
 	//}
 	//
+	//// ValidationError describes a value violating a schema keyword
+	//type ValidationError struct {
+	//	// JSON pointer to the invalid value, empty for the validated value itself
+	//	InstancePointer string
+	//
+	//	// JSON pointer of the violated schema
+	//	SchemaPointer string
+	//
+	//	// Violated keyword, e.g. required
+	//	Keyword string
+	//
+	//	// Human readable description of the violation
+	//	Message string
+	//}
+	//
+	//func (e *ValidationError) Error() string {
+	//	return e.Message
+	//}
+	//
+	//// ValidationErrors collects multiple validation errors of a single value
+	//type ValidationErrors []*ValidationError
+	//
+	//func (e ValidationErrors) Error() string {
+	//	msg := ""
+	//	for i, err := range e {
+	//		if i > 0 {
+	//			msg += "; "
+	//		}
+	//		msg += err.Error()
+	//	}
+	//	return msg
+	//}
+	//
+	//// prefixes the instance pointers of validation errors with the pointer of the validated value
+	//func prefixValidationError(err error, prefix string) error {
+	//	switch e := err.(type) {
+	//	case *ValidationError:
+	//		p := *e
+	//		p.InstancePointer = prefix + p.InstancePointer
+	//		return &p
+	//	case ValidationErrors:
+	//		ps := make(ValidationErrors, 0, len(e))
+	//		for _, ve := range e {
+	//			p := *ve
+	//			p.InstancePointer = prefix + p.InstancePointer
+	//			ps = append(ps, &p)
+	//		}
+	//		return ps
+	//	}
+	//	return err
+	//}
+	//
 	//func newString(s string) *string {
 	//	return &s
 	//}
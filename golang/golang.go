@@ -49,33 +49,163 @@ Results in a User type with a Validate method:
 
 	func (t *User) Validate() error {
 		if t.ID == nil {
-			return errors.New("invalid user: missing id")
+			return &ValidationError{
+				InstancePointer: "/id",
+				SchemaPointer:   "#/definitions/user",
+				Keyword:         "required",
+				Message:         "invalid user: missing id",
+			}
 		}
 
 		return nil
 	}
+
+Failed validations are reported as *ValidationError, which is generated alongside the types.
+The InstancePointer of errors of nested values is relative to the validated value.
+
+Properties of primitive types are checked against multipleOf, maximum, exclusiveMaximum, minimum,
+exclusiveMinimum, maxLength, minLength and pattern. Patterns are compiled once into package level variables:
+
+	var patternUserID = regexp.MustCompile("^[a-z0-9]+$")
+
+Tuples described by prefixItems or a list of items result in a slice of the go type shared by all items,
+or of interface{} for items of different types. The types of items at their positions are enforced by runtime
+validation only, as are unevaluatedItems and unevaluatedProperties.
+
+Array types are checked against maxItems, minItems and uniqueItems, comparing struct items with reflect.DeepEqual.
+Items are matched against contains by converting them to the named contains type through their JSON encoding.
+Items of primitive types are matched against the numeric, string, enum and const keywords of a contains schema
+without a named type, other contains schemas are enforced by runtime validation only.
+
+Object types are checked against maxProperties, minProperties and dependentRequired, as well as the required properties
+of dependentSchemas and dependencies. Keys of map types and AdditionalProperties are checked against the string keywords
+of propertyNames. All other keywords of dependent schemas are enforced by runtime validation only.
+
+Object types with if and then or else evaluate the if schema with a generated helper method and apply the required
+properties and property keywords of the matching branch:
+
+	func (t *Payment) matchesPaymentIf() bool {
+		if t.Kind == nil {
+			return false
+		}
+		...
+		return true
+	}
+
+The helper evaluates required as well as the numeric, string, enum and const keywords of properties of the if schema,
+all other keywords of if, then and else are enforced by runtime validation only.
+
+Schemas with enum or const values result in a named type with a constant per value:
+
+	type Status string
+
+	const (
+		StatusActive   Status = "active"
+		StatusInactive Status = "inactive"
+	)
+
+Object types embed the struct types of their allOf schemas.
+Schemas with oneOf or anyOf result in a struct with a pointer field per variant.
+Its UnmarshalJSON method decodes into all variants the value is valid for, MarshalJSON encodes the first set variant:
+
+	type Pet struct {
+		Cat *Cat
+		Dog *Dog
+	}
+
+Objects without properties whose values are described by additionalProperties or patternProperties result in a map type.
+Objects with properties keep all other values in an AdditionalProperties map:
+
+	type Labels map[string]string
+
+	type Actor struct {
+		Name                 *string             `json:"name,omitempty"`
+		AdditionalProperties map[string]Location `json:"-"`
+	}
+
+An additionalProperties value of false is enforced by runtime validation only.
+Other boolean schemas are understood by the generator: true allows any value, e.g. a map[string]interface{}
+for additionalProperties true of an object without properties, false drops a property and limits the length
+of arrays whose items or additionalItems are false.
+
+Properties whose type list allows null besides a single other type, or whose enum allows null besides values
+of a single type, result in a field of the generated generic Nullable type, distinguishing an absent property
+from an explicit null:
+
+	type Profile struct {
+		Nickname Nullable[string] `json:"nickname,omitzero"`
+	}
+
+Generated code requires Go 1.24 or later, as Nullable is generic and omitted through the omitzero option of encoding/json.
+
+Properties allowing any value, e.g. {} or true, result in an interface{} field, properties constrained
+without a type, e.g. by an enum of a string and a number, in a json.RawMessage field:
+
+	type Document struct {
+		Kind json.RawMessage `json:"kind,omitempty"`
+		Meta interface{}     `json:"meta,omitempty"`
+	}
+
+Recursive types reference themselves through pointers, slices and maps, e.g. Nullable[*Category]
+for a nullable parent category, so their Validate funcs terminate at the end of the decoded value.
+
+Schemas with several types besides null result in a struct with a field per type, tagged with the type of the
+decoded value:
+
+	type Score struct {
+		// JSON type of the decoded value, one of string, integer
+		Type string
+
+		String  string
+		Integer int
+	}
 */
 package golang
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
+	"go/ast"
 	"go/format"
+	"go/parser"
+	"go/token"
+	"math"
+	"math/big"
+	"path"
 	"regexp"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/tfkhsr/jsonschema"
 )
 
+// Options control the generated source
+type Options struct {
+	// Generate Validate() methods reporting all failed validations as ValidationErrors
+	// instead of returning the first failed validation
+	AllErrors bool
+}
+
 // Generates go src from an jsonschema.Index without imports and package
 func Src(idx *jsonschema.Index) ([]byte, error) {
+	return SrcWithOptions(idx, Options{})
+}
+
+// Generates go src from an jsonschema.Index without imports and package using the given options
+func SrcWithOptions(idx *jsonschema.Index, opts Options) ([]byte, error) {
 	typ, err := generateGoTypes(idx)
 	if err != nil {
 		return nil, err
 	}
 
-	vf, err := generateGoTypesValidateFuncs(idx)
+	vf, err := generateGoTypesValidateFuncs(idx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	et, err := generateGoValidationErrorTypes(opts)
 	if err != nil {
 		return nil, err
 	}
@@ -88,6 +218,14 @@ func Src(idx *jsonschema.Index) ([]byte, error) {
 	w := &bytes.Buffer{}
 	fmt.Fprintf(w, "%s", typ)
 	fmt.Fprintf(w, "%s", vf)
+	fmt.Fprintf(w, "%s", et)
+	if bytes.Contains(typ, []byte("Nullable[")) {
+		nt, err := generateGoNullableType()
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(w, "%s", nt)
+	}
 	fmt.Fprintf(w, "%s", pt)
 
 	return format.Source(w.Bytes())
@@ -95,7 +233,12 @@ func Src(idx *jsonschema.Index) ([]byte, error) {
 
 // Generates go src for a package including imports and package
 func PackageSrc(idx *jsonschema.Index, pack string) ([]byte, error) {
-	src, err := Src(idx)
+	return PackageSrcWithOptions(idx, pack, Options{})
+}
+
+// Generates go src for a package including imports and package using the given options
+func PackageSrcWithOptions(idx *jsonschema.Index, pack string, opts Options) ([]byte, error) {
+	src, err := SrcWithOptions(idx, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -113,15 +256,27 @@ import (
 	return format.Source(w.Bytes())
 }
 
-// Returns a list of required imports
+// Returns a list of required imports, i.e. the packages whose identifiers are used as qualifiers in src
 func Imports(src []byte) []string {
 	i := []string{}
-	srcString := string(src)
-	if strings.Contains(srcString, "errors") {
-		i = append(i, "errors")
+	f, err := parser.ParseFile(token.NewFileSet(), "", append([]byte("package p\n"), src...), 0)
+	if err != nil {
+		return i
 	}
-	if strings.Contains(srcString, "fmt") {
-		i = append(i, "fmt")
+	used := map[string]bool{}
+	ast.Inspect(f, func(n ast.Node) bool {
+		// qualifiers are not declared in src, unlike variables and fields
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if id, ok := sel.X.(*ast.Ident); ok && id.Obj == nil {
+				used[id.Name] = true
+			}
+		}
+		return true
+	})
+	for _, p := range []string{"encoding/json", "errors", "fmt", "math", "reflect", "regexp", "sort", "strconv", "unicode/utf8"} {
+		if used[path.Base(p)] {
+			i = append(i, p)
+		}
 	}
 	sort.Strings(i)
 	return i
@@ -131,7 +286,8 @@ func Imports(src []byte) []string {
 func generateGoTypes(idx *jsonschema.Index) ([]byte, error) {
 	w := bytes.NewBufferString("\n")
 	for _, k := range sortedMapKeysbyName(idx) {
-		t, err := generateGoType((*idx)[k], idx)
+		s, _ := idx.Get(k)
+		t, err := generateGoType(s, idx)
 		if err != nil {
 			return nil, err
 		}
@@ -146,225 +302,1998 @@ func generateGoTypes(idx *jsonschema.Index) ([]byte, error) {
 // Generates the type definition for a schema
 func generateGoType(s *jsonschema.Schema, idx *jsonschema.Index) ([]byte, error) {
 	w := &bytes.Buffer{}
-	switch s.Type {
+	if typ := enumGoType(s); typ != "" {
+		generateGoEnumType(w, s, typ)
+		return format.Source(w.Bytes())
+	}
+	if variants := goUnionVariants(s, idx); len(variants) > 0 {
+		err := generateGoUnionType(w, s, idx, variants)
+		if err != nil {
+			return nil, err
+		}
+		return format.Source(w.Bytes())
+	}
+	if types := goTypeUnionTypes(s); len(types) > 0 {
+		generateGoTypeUnionType(w, s, types)
+		return format.Source(w.Bytes())
+	}
+	switch typeOf(s) {
 	case "object":
+		vt, _, err := goMapValueType(s, idx)
+		if err != nil {
+			return nil, err
+		}
+		if isMapType(s, idx) {
+			fmt.Fprintf(w, "type %v map[string]%v\n", s.Name, vt)
+			break
+		}
+
 		fmt.Fprintf(w, "type %v struct {\n", s.Name)
-		for _, k := range sortedMapKeys(&s.Properties) {
+		for _, a := range s.AllOf {
+			p, err := idx.Resolve(a)
+			if err != nil {
+				return nil, err
+			}
+			if isStructType(p, idx) {
+				fmt.Fprintf(w, "\t%v\n", p.Name)
+			}
+		}
+		for _, k := range sortedMapKeys(s.Properties) {
 			ref := generateGoRef(s.Properties[k], idx)
 			if ref != "" {
 				fmt.Fprintf(w, "\t%s\n", ref)
 			}
 		}
+		if vt != "" {
+			fmt.Fprintf(w, "\tAdditionalProperties map[string]%v `json:\"-\"`\n", vt)
+		}
 		fmt.Fprintf(w, "}\n")
+		if vt != "" {
+			err := generateGoAdditionalPropertiesFuncs(w, s, idx, vt)
+			if err != nil {
+				return nil, err
+			}
+		}
 	case "array":
-		p, err := resolvRefToSchema(s.Items, idx)
-		if err != nil {
-			return nil, err
+		typ := ""
+		if is := goItemSchema(s, idx); is != nil {
+			var err error
+			typ, err = goType(is, idx)
+			if err != nil {
+				return nil, err
+			}
 		}
-		typ := s.Items.Type
-		if p != s.Items {
-			typ = p.Name
+		if typ == "" {
+			typ = "interface{}"
 		}
 		fmt.Fprintf(w, "type %v []%v\n", s.Name, typ)
 	}
 	return format.Source(w.Bytes())
 }
 
-// Generates the inline reference in a type for a schema
-func generateGoRef(s *jsonschema.Schema, idx *jsonschema.Index) string {
-	switch s.Type {
-	case "string":
-		return fmt.Sprintf("%v *string `json:\"%v,omitempty\"`", s.Name, s.JSONName)
-	case "integer":
-		return fmt.Sprintf("%v *int `json:\"%v,omitempty\"`", s.Name, s.JSONName)
-	case "number":
-		return fmt.Sprintf("%v *float64 `json:\"%v,omitempty\"`", s.Name, s.JSONName)
-	case "boolean":
-		return fmt.Sprintf("%v *bool `json:\"%v,omitempty\"`", s.Name, s.JSONName)
-	case "object":
-		return fmt.Sprintf("%v *%v `json:\"%v,omitempty\"`", s.Name, s.Name, s.JSONName)
-	case "array":
-		return fmt.Sprintf("%v *%v `json:\"%v,omitempty\"`", s.Name, s.Name, s.JSONName)
-	case "ref":
-		ref := (*idx)[s.Ref]
-		return fmt.Sprintf("%v *%v `json:\"%v,omitempty\"`", s.Name, ref.Name, ref.JSONName)
+// Generates a named type including constants for all values of an enum or const schema
+func generateGoEnumType(w *bytes.Buffer, s *jsonschema.Schema, typ string) {
+	fmt.Fprintf(w, "type %v %v\n\n", s.Name, typ)
+	fmt.Fprintf(w, "const (\n")
+	for _, v := range goEnumValues(s) {
+		fmt.Fprintf(w, "\t%v %v = %v\n", v.Name, s.Name, v.Literal)
 	}
-	return ""
+	fmt.Fprintf(w, ")\n")
 }
 
-// Generates the formatted go validate funcs for all types in the index
-func generateGoTypesValidateFuncs(idx *jsonschema.Index) ([]byte, error) {
-	w := bytes.NewBufferString("\n")
-	for _, k := range sortedMapKeysbyName(idx) {
-		t, err := generateGoTypeValidateFunc((*idx)[k], idx)
-		if err != nil {
-			return nil, err
-		}
-		if string(t) != "" {
-			fmt.Fprintf(w, "%s\n", t)
-		}
+// Generates JSON funcs of a struct keeping all properties without field in AdditionalProperties
+func generateGoAdditionalPropertiesFuncs(w *bytes.Buffer, s *jsonschema.Schema, idx *jsonschema.Index, vt string) error {
+	names, err := goStructJSONNames(s, idx)
+	if err != nil {
+		return err
 	}
 
-	return format.Source(w.Bytes())
+	fmt.Fprintf(w, "\n// UnmarshalJSON decodes properties into fields and all other properties into AdditionalProperties\n")
+	fmt.Fprintf(w, "func (t *%v) UnmarshalJSON(b []byte) error {\n", s.Name)
+	fmt.Fprintf(w, "\ttype plain %v\n", s.Name)
+	fmt.Fprintf(w, "\terr := json.Unmarshal(b, (*plain)(t))\n")
+	fmt.Fprintf(w, "\tif err != nil {\n")
+	fmt.Fprintf(w, "\t\treturn err\n")
+	fmt.Fprintf(w, "\t}\n")
+	fmt.Fprintf(w, "\tvar raw map[string]json.RawMessage\n")
+	fmt.Fprintf(w, "\terr = json.Unmarshal(b, &raw)\n")
+	fmt.Fprintf(w, "\tif err != nil {\n")
+	fmt.Fprintf(w, "\t\treturn err\n")
+	fmt.Fprintf(w, "\t}\n")
+	for _, n := range names {
+		fmt.Fprintf(w, "\tdelete(raw, %q)\n", n)
+	}
+	fmt.Fprintf(w, "\tt.AdditionalProperties = nil\n")
+	fmt.Fprintf(w, "\tfor k, r := range raw {\n")
+	fmt.Fprintf(w, "\t\tvar v %v\n", vt)
+	fmt.Fprintf(w, "\t\terr := json.Unmarshal(r, &v)\n")
+	fmt.Fprintf(w, "\t\tif err != nil {\n")
+	fmt.Fprintf(w, "\t\t\treturn err\n")
+	fmt.Fprintf(w, "\t\t}\n")
+	fmt.Fprintf(w, "\t\tif t.AdditionalProperties == nil {\n")
+	fmt.Fprintf(w, "\t\t\tt.AdditionalProperties = map[string]%v{}\n", vt)
+	fmt.Fprintf(w, "\t\t}\n")
+	fmt.Fprintf(w, "\t\tt.AdditionalProperties[k] = v\n")
+	fmt.Fprintf(w, "\t}\n")
+	fmt.Fprintf(w, "\treturn nil\n")
+	fmt.Fprintf(w, "}\n\n")
+
+	fmt.Fprintf(w, "// MarshalJSON encodes fields and AdditionalProperties into a single object\n")
+	fmt.Fprintf(w, "func (t %v) MarshalJSON() ([]byte, error) {\n", s.Name)
+	fmt.Fprintf(w, "\ttype plain %v\n", s.Name)
+	fmt.Fprintf(w, "\tb, err := json.Marshal(plain(t))\n")
+	fmt.Fprintf(w, "\tif err != nil || len(t.AdditionalProperties) == 0 {\n")
+	fmt.Fprintf(w, "\t\treturn b, err\n")
+	fmt.Fprintf(w, "\t}\n")
+	fmt.Fprintf(w, "\tvar raw map[string]json.RawMessage\n")
+	fmt.Fprintf(w, "\terr = json.Unmarshal(b, &raw)\n")
+	fmt.Fprintf(w, "\tif err != nil {\n")
+	fmt.Fprintf(w, "\t\treturn nil, err\n")
+	fmt.Fprintf(w, "\t}\n")
+	fmt.Fprintf(w, "\tfor k, v := range t.AdditionalProperties {\n")
+	fmt.Fprintf(w, "\t\tif _, ok := raw[k]; ok {\n")
+	fmt.Fprintf(w, "\t\t\tcontinue\n")
+	fmt.Fprintf(w, "\t\t}\n")
+	fmt.Fprintf(w, "\t\traw[k], err = json.Marshal(v)\n")
+	fmt.Fprintf(w, "\t\tif err != nil {\n")
+	fmt.Fprintf(w, "\t\t\treturn nil, err\n")
+	fmt.Fprintf(w, "\t\t}\n")
+	fmt.Fprintf(w, "\t}\n")
+	fmt.Fprintf(w, "\treturn json.Marshal(raw)\n")
+	fmt.Fprintf(w, "}\n")
+	return nil
 }
 
-// Generates the validate func for a schema
-func generateGoTypeValidateFunc(s *jsonschema.Schema, idx *jsonschema.Index) ([]byte, error) {
-	w := &bytes.Buffer{}
-	fmt.Fprintf(w, "func (t *%v) Validate() error {\n", s.Name)
-	errorVarExists := ":"
-	switch s.Type {
-	case "object":
-		checks, err := generateRequiredValidationCheck(idx, s)
+// returns the JSON names of all fields of a struct including embedded structs
+func goStructJSONNames(s *jsonschema.Schema, idx *jsonschema.Index) ([]string, error) {
+	names := sortedMapKeys(s.Properties)
+	for _, a := range s.AllOf {
+		p, err := idx.Resolve(a)
 		if err != nil {
 			return nil, err
 		}
-		if string(checks) != "" {
-			fmt.Fprintf(w, "\t%s\n", checks)
-		}
-
-		// Validate() calls of non-primitive type properties
-		for _, k := range sortedMapKeys(&s.Properties) {
-			p, err := resolvRefToSchema(s.Properties[k], idx)
+		if isStructType(p, idx) {
+			n, err := goStructJSONNames(p, idx)
 			if err != nil {
 				return nil, err
 			}
+			names = append(names, n...)
+		}
+	}
+	return names, nil
+}
 
-			if p.Type == "object" || p.Type == "array" {
-				fmt.Fprintf(w, "\terr %s= t.%v.Validate()\n", errorVarExists, p.Name)
-				fmt.Fprintf(w, "\tif err != nil {\n")
-				fmt.Fprintf(w, "\t\treturn err\n")
-				fmt.Fprintf(w, "\t}\n")
-				errorVarExists = ""
-			}
+// Generates a struct with a field per variant of a oneOf or anyOf schema, decoding into all valid variants
+func generateGoUnionType(w *bytes.Buffer, s *jsonschema.Schema, idx *jsonschema.Index, variants []*jsonschema.Schema) error {
+	fmt.Fprintf(w, "type %v struct {\n", s.Name)
+	for _, v := range variants {
+		typ, err := goType(v, idx)
+		if err != nil {
+			return err
 		}
-	case "array":
-		as, err := resolvRefToSchema(s.Items, idx)
+		fmt.Fprintf(w, "\t%v *%v\n", v.Name, typ)
+	}
+	fmt.Fprintf(w, "}\n\n")
+
+	keyword, msg := goUnionValidationMessage(s, variants)
+	fmt.Fprintf(w, "// UnmarshalJSON decodes into all variants the raw value is valid for\n")
+	fmt.Fprintf(w, "func (t *%v) UnmarshalJSON(b []byte) error {\n", s.Name)
+	fmt.Fprintf(w, "\t*t = %v{}\n", s.Name)
+	var unset []string
+	for i, v := range variants {
+		typ, err := goType(v, idx)
 		if err != nil {
-			return nil, err
+			return err
 		}
+		fmt.Fprintf(w, "\tvar v%v %v\n", i, typ)
+		if isNamedType(v, idx) {
+			fmt.Fprintf(w, "\tif json.Unmarshal(b, &v%v) == nil && v%v.Validate() == nil {\n", i, i)
+		} else {
+			fmt.Fprintf(w, "\tif json.Unmarshal(b, &v%v) == nil {\n", i)
+		}
+		fmt.Fprintf(w, "\t\tt.%v = &v%v\n", v.Name, i)
+		fmt.Fprintf(w, "\t}\n")
+		unset = append(unset, fmt.Sprintf("t.%v == nil", v.Name))
+	}
+	fmt.Fprintf(w, "\tif %v {\n", strings.Join(unset, " && "))
+	fmt.Fprintf(w, "\t\treturn %v\n", generateValidationError("", s.Pointer, keyword, msg))
+	fmt.Fprintf(w, "\t}\n")
+	fmt.Fprintf(w, "\treturn nil\n")
+	fmt.Fprintf(w, "}\n\n")
 
-		if as.Type == "object" || as.Type == "array" {
-			fmt.Fprintf(w, "\tfor _, a := range *t {\n")
-			fmt.Fprintf(w, "\t\t\terr %s= a.Validate()\n", errorVarExists)
-			fmt.Fprintf(w, "\t\tif err != nil {\n")
-			fmt.Fprintf(w, "\t\t\treturn err\n")
+	fmt.Fprintf(w, "// MarshalJSON encodes the first set variant\n")
+	fmt.Fprintf(w, "func (t %v) MarshalJSON() ([]byte, error) {\n", s.Name)
+	fmt.Fprintf(w, "\tswitch {\n")
+	for _, v := range variants {
+		fmt.Fprintf(w, "\tcase t.%v != nil:\n", v.Name)
+		fmt.Fprintf(w, "\t\treturn json.Marshal(t.%v)\n", v.Name)
+	}
+	fmt.Fprintf(w, "\t}\n")
+	fmt.Fprintf(w, "\treturn []byte(\"null\"), nil\n")
+	fmt.Fprintf(w, "}\n")
+	return nil
+}
+
+// go field names and types of the values of a type union
+var goTypeUnionFields = map[string][2]string{
+	"string":  {"String", "string"},
+	"integer": {"Integer", "int"},
+	"number":  {"Number", "float64"},
+	"boolean": {"Boolean", "bool"},
+	"object":  {"Object", "map[string]interface{}"},
+	"array":   {"Array", "[]interface{}"},
+}
+
+// Generates a struct with a field per type of a schema with several types, tagged with the type of the decoded value
+func generateGoTypeUnionType(w *bytes.Buffer, s *jsonschema.Schema, types []string) {
+	allowed := map[string]bool{}
+	fmt.Fprintf(w, "type %v struct {\n", s.Name)
+	fmt.Fprintf(w, "\t// JSON type of the decoded value, one of %v\n", strings.Join(s.Types, ", "))
+	fmt.Fprintf(w, "\tType string\n\n")
+	for _, t := range types {
+		f := goTypeUnionFields[t]
+		fmt.Fprintf(w, "\t%v %v\n", f[0], f[1])
+		allowed[t] = true
+	}
+	fmt.Fprintf(w, "}\n\n")
+
+	fmt.Fprintf(w, "// UnmarshalJSON decodes the value into the field of its type\n")
+	fmt.Fprintf(w, "func (t *%v) UnmarshalJSON(b []byte) error {\n", s.Name)
+	fmt.Fprintf(w, "\t*t = %v{}\n", s.Name)
+	fmt.Fprintf(w, "\tvar v interface{}\n")
+	fmt.Fprintf(w, "\terr := json.Unmarshal(b, &v)\n")
+	fmt.Fprintf(w, "\tif err != nil {\n")
+	fmt.Fprintf(w, "\t\treturn err\n")
+	fmt.Fprintf(w, "\t}\n")
+	fmt.Fprintf(w, "\tswitch v := v.(type) {\n")
+	if s.Nullable() {
+		fmt.Fprintf(w, "\tcase nil:\n")
+		fmt.Fprintf(w, "\t\tt.Type = \"null\"\n")
+	}
+	if allowed["string"] {
+		fmt.Fprintf(w, "\tcase string:\n")
+		fmt.Fprintf(w, "\t\tt.Type, t.String = \"string\", v\n")
+	}
+	if allowed["integer"] || allowed["number"] {
+		fmt.Fprintf(w, "\tcase float64:\n")
+		if allowed["integer"] {
+			fmt.Fprintf(w, "\t\tif v == math.Trunc(v) {\n")
+			fmt.Fprintf(w, "\t\t\tt.Type, t.Integer = \"integer\", int(v)\n")
+			fmt.Fprintf(w, "\t\t\tbreak\n")
 			fmt.Fprintf(w, "\t\t}\n")
-			fmt.Fprintf(w, "\t}\n")
-			errorVarExists = ""
 		}
-	default:
-		return nil, nil
+		if allowed["number"] {
+			fmt.Fprintf(w, "\t\tt.Type, t.Number = \"number\", v\n")
+		}
+	}
+	if allowed["boolean"] {
+		fmt.Fprintf(w, "\tcase bool:\n")
+		fmt.Fprintf(w, "\t\tt.Type, t.Boolean = \"boolean\", v\n")
+	}
+	if allowed["object"] {
+		fmt.Fprintf(w, "\tcase map[string]interface{}:\n")
+		fmt.Fprintf(w, "\t\tt.Type, t.Object = \"object\", v\n")
 	}
+	if allowed["array"] {
+		fmt.Fprintf(w, "\tcase []interface{}:\n")
+		fmt.Fprintf(w, "\t\tt.Type, t.Array = \"array\", v\n")
+	}
+	fmt.Fprintf(w, "\t}\n")
+	fmt.Fprintf(w, "\tif t.Type == \"\" {\n")
+	fmt.Fprintf(w, "\t\treturn %v\n", generateValidationError("", s.Pointer, "type", goTypeUnionValidationMessage(s)))
+	fmt.Fprintf(w, "\t}\n")
 	fmt.Fprintf(w, "\treturn nil\n")
+	fmt.Fprintf(w, "}\n\n")
+
+	fmt.Fprintf(w, "// MarshalJSON encodes the field of the type\n")
+	fmt.Fprintf(w, "func (t %v) MarshalJSON() ([]byte, error) {\n", s.Name)
+	fmt.Fprintf(w, "\tswitch t.Type {\n")
+	for _, t := range types {
+		fmt.Fprintf(w, "\tcase %q:\n", t)
+		fmt.Fprintf(w, "\t\treturn json.Marshal(t.%v)\n", goTypeUnionFields[t][0])
+	}
+	fmt.Fprintf(w, "\t}\n")
+	fmt.Fprintf(w, "\treturn []byte(\"null\"), nil\n")
 	fmt.Fprintf(w, "}\n")
+}
 
-	return format.Source(w.Bytes())
+// returns the message of a failed type validation of a type union
+func goTypeUnionValidationMessage(s *jsonschema.Schema) string {
+	return fmt.Sprintf("invalid %v: must be %v", s.JSONName, strings.Join(s.Types, " or "))
 }
 
-// generate "required" validation check
-func generateRequiredValidationCheck(idx *jsonschema.Index, s *jsonschema.Schema) ([]byte, error) {
-	if len(s.Required) == 0 {
-		return nil, nil
+// returns the types besides null of a schema generated as type union, or nil if the schema is none
+func goTypeUnionTypes(s *jsonschema.Schema) []string {
+	if typeOf(s) != "" {
+		return nil
 	}
-	w := &bytes.Buffer{}
-	for _, p := range s.Required {
-		ptr := s.Pointer + "/properties/" + p
-		rs := (*idx)[ptr]
-		if rs == nil {
-			return nil, fmt.Errorf("jsonschema: %v does not exist in index", ptr)
+	var types []string
+	for _, t := range s.Types {
+		if _, ok := goTypeUnionFields[t]; ok {
+			types = append(types, t)
 		}
-		fmt.Fprintf(w, "if t.%v == nil {\n", rs.Name)
-		fmt.Fprintf(w, "\treturn errors.New(\"invalid %v: missing %v\")\n", s.JSONName, p)
-		fmt.Fprintf(w, "}\n")
 	}
-	return w.Bytes(), nil
+	if len(types) < 2 {
+		return nil
+	}
+	return types
 }
 
-// Generates primitive type new funcs
-func generateGoPrimitiveTypesNewFuncs() ([]byte, error) {
-	b := bytes.NewBufferString(`
-func newString(s string) *string {
-	return &s
+// returns the keyword and message of a failed oneOf or anyOf validation
+func goUnionValidationMessage(s *jsonschema.Schema, variants []*jsonschema.Schema) (string, string) {
+	var names []string
+	for _, v := range variants {
+		names = append(names, v.Name)
+	}
+	if len(s.OneOf) > 0 {
+		return "oneOf", fmt.Sprintf("invalid %v: must match exactly one of %v", s.JSONName, strings.Join(names, ", "))
+	}
+	return "anyOf", fmt.Sprintf("invalid %v: must match at least one of %v", s.JSONName, strings.Join(names, ", "))
 }
 
-func newInt(i int) *int {
-	return &i
+// returns the resolved variants of a oneOf or anyOf schema generated as union type, or nil if the schema is none
+func goUnionVariants(s *jsonschema.Schema, idx *jsonschema.Index) []*jsonschema.Schema {
+	return goUnionVariantsOf(s, idx, map[*jsonschema.Schema]bool{})
 }
 
-func newFloat(f float64) *float64 {
-	return &f
+// goUnionVariants of a schema reached through the variants of the unions seen, a union which is its own variant is none
+func goUnionVariantsOf(s *jsonschema.Schema, idx *jsonschema.Index, seen map[*jsonschema.Schema]bool) []*jsonschema.Schema {
+	if typeOf(s) != "" && typeOf(s) != "object" || seen[s] {
+		return nil
+	}
+	seen[s] = true
+	defer delete(seen, s)
+	schemas := s.OneOf
+	if len(schemas) == 0 {
+		schemas = s.AnyOf
+	}
+
+	var variants []*jsonschema.Schema
+	names := map[string]bool{}
+	for _, v := range schemas {
+		p, err := idx.Resolve(v)
+		if err != nil {
+			return nil
+		}
+		typ, err := goTypeOf(p, idx, seen)
+		if err != nil || typ == "" {
+			return nil
+		}
+		if names[p.Name] {
+			continue
+		}
+		names[p.Name] = true
+		variants = append(variants, p)
+	}
+	return variants
 }
 
-func newBool(b bool) *bool {
-	return &b
+// Generates the inline reference in a type for a schema
+func generateGoRef(s *jsonschema.Schema, idx *jsonschema.Index) string {
+	typ, err := goType(s, idx)
+	if err != nil {
+		return ""
+	}
+	if typ == "" {
+		typ = goUntypedType(s, idx)
+		if typ == "" {
+			return ""
+		}
+		return fmt.Sprintf("%v %v `json:\"%v,omitempty\"`", s.Name, typ, s.JSONName)
+	}
+	if isNullableField(s, idx) {
+		if p, err := idx.Resolve(s); err == nil && containsNullable(p, p, idx, map[*jsonschema.Schema]bool{}) {
+			typ = "*" + typ
+		}
+		return fmt.Sprintf("%v Nullable[%v] `json:\"%v,omitzero\"`", s.Name, typ, s.JSONName)
+	}
+	return fmt.Sprintf("%v *%v `json:\"%v,omitempty\"`", s.Name, typ, s.JSONName)
 }
-	`)
 
-	return format.Source(b.Bytes())
+// returns the go type of a schema without go type, interface{} for schemas allowing any value
+// and json.RawMessage for values constrained without a type, e.g. by an enum of several types
+func goUntypedType(s *jsonschema.Schema, idx *jsonschema.Index) string {
+	p, err := idx.Resolve(s)
+	switch {
+	case err != nil || p.Boolean != nil && !*p.Boolean:
+		return ""
+	case p.IsAny():
+		return "interface{}"
+	case p.Type == "" && len(p.Types) == 0:
+		return "json.RawMessage"
+	}
+	return ""
+}
+
+// returns true if the struct of schema s holds a value of target through nullable fields.
+// Nullable holds its value directly, so recursive types need a pointer in Nullable to be finite.
+func containsNullable(s *jsonschema.Schema, target *jsonschema.Schema, idx *jsonschema.Index, seen map[*jsonschema.Schema]bool) bool {
+	if seen[s] || !isStructType(s, idx) {
+		return false
+	}
+	seen[s] = true
+
+	props := []*jsonschema.Schema{}
+	for _, sch := range append([]*jsonschema.Schema{s}, s.AllOf...) {
+		if p, err := idx.Resolve(sch); err == nil {
+			for _, prop := range p.Properties {
+				props = append(props, prop)
+			}
+		}
+	}
+	for _, prop := range props {
+		p, err := idx.Resolve(prop)
+		if err != nil || !isNullableField(prop, idx) {
+			continue
+		}
+		if p == target || containsNullable(p, target, idx, seen) {
+			return true
+		}
+	}
+	return false
+}
+
+// go expressions accessing the field of a property in a validate func
+type goField struct {
+	// condition of the property being present, including null
+	Set string
+
+	// condition of the property being absent
+	Unset string
+
+	// condition of the property being present and not null
+	Valid string
+
+	// value of a valid field
+	Value string
+
+	// addressable value of a valid field to call methods on
+	Ref string
 }
 
-// returns a schema or referenced schema
-func resolvRefToSchema(s *jsonschema.Schema, idx *jsonschema.Index) (*jsonschema.Schema, error) {
-	if s.Type != "ref" {
-		return s, nil
+// returns the go expressions accessing the field of a property
+func goFieldOf(prop *jsonschema.Schema, idx *jsonschema.Index) goField {
+	f := "t." + prop.Name
+	if isNullableField(prop, idx) {
+		return goField{f + ".Set", "!" + f + ".Set", f + ".Valid", f + ".Value", f + ".Value"}
 	}
-	ref := (*idx)[s.Ref]
-	if ref == nil {
-		return nil, fmt.Errorf("jsonschema: %v does not exist in index", s.Ref)
+	if typ, err := goType(prop, idx); err == nil && typ == "" {
+		return goField{f + " != nil", f + " == nil", f + " != nil", f, f}
 	}
-	return ref, nil
+	return goField{f + " != nil", f + " == nil", f + " != nil", "*" + f, f}
 }
 
-// creates a go friendly name from a JSON pointer
-func goNameFromPointer(pointer string) string {
-	p := strings.Split(pointer, "/")
-	return goNameFromStrings(p[len(p)-1])
+// returns true if the field of a property distinguishes null from an absent value
+func isNullableField(prop *jsonschema.Schema, idx *jsonschema.Index) bool {
+	p, err := idx.Resolve(prop)
+	if err != nil {
+		return false
+	}
+	// the types next to a $ref apply in 2019-09 and 2020-12
+	if prop.IsRef() && (prop.Draft == jsonschema.Draft201909 || prop.Draft == jsonschema.Draft202012) && prop.Nullable() {
+		return true
+	}
+	return p.Nullable() || isNullableEnum(p)
 }
 
-// creates a go friendly name from string parts
-func goNameFromStrings(parts ...string) string {
-	name := ""
-	re := regexp.MustCompile("{|}|-|_")
-	for _, p := range parts {
-		c := re.ReplaceAllString(p, "")
-		switch c {
-		case "id":
-			name += "ID"
-		case "url":
-			name += "URL"
-		case "api":
-			name += "API"
-		default:
-			name += strings.Title(c)
+// returns true if the enum or const of a schema with a go enum type allows null
+func isNullableEnum(s *jsonschema.Schema) bool {
+	if enumGoType(s) == "" {
+		return false
+	}
+	if s.HasConst() {
+		return s.Const == nil
+	}
+	for _, v := range s.Enum {
+		if v == nil {
+			return true
 		}
 	}
-	return name
+	return false
 }
 
-// returns a json friendly name from a pointer
-func jsonNameFromPointer(pointer string) string {
-	p := strings.Split(pointer, "/")
-	return p[len(p)-1]
+// returns the go type used to reference a schema from other types, or "" if there is none
+func goType(s *jsonschema.Schema, idx *jsonschema.Index) (string, error) {
+	return goTypeOf(s, idx, map[*jsonschema.Schema]bool{})
 }
 
-// returns map keys sorted by alphapet
-func sortedMapKeys(m *jsonschema.Index) []string {
-	var keys []string
-	for k, _ := range *m {
-		keys = append(keys, k)
+// goType of a schema reached through the variants of the unions seen
+func goTypeOf(s *jsonschema.Schema, idx *jsonschema.Index, seen map[*jsonschema.Schema]bool) (string, error) {
+	p, err := idx.Resolve(s)
+	if err != nil {
+		return "", err
 	}
-	sort.Strings(keys)
-	return keys
+	if isNamedTypeOf(p, idx, seen) {
+		return p.Name, nil
+	}
+	switch p.Type {
+	case "string":
+		return "string", nil
+	case "integer":
+		return "int", nil
+	case "number":
+		return "float64", nil
+	case "boolean":
+		return "bool", nil
+	}
+	return "", nil
 }
 
-// returns map keys sorted by schema names
-func sortedMapKeysbyName(m *jsonschema.Index) []string {
-	var schemas []*jsonschema.Schema
-	for _, v := range *m {
-		schemas = append(schemas, v)
+// returns the resolved schema of all items of an array schema,
+// or nil if there is none or the items of a tuple are of different go types
+func goItemSchema(s *jsonschema.Schema, idx *jsonschema.Index) *jsonschema.Schema {
+	prefix, rest := s.TupleItems()
+	schemas := prefix
+	if rest != nil && (rest.Boolean == nil || *rest.Boolean) {
+		schemas = append(schemas[:len(schemas):len(schemas)], rest)
+	} else if rest == nil && len(prefix) > 0 {
+		// items after the prefix are of any type
+		return nil
+	}
+
+	var item *jsonschema.Schema
+	for _, sch := range schemas {
+		p, err := idx.Resolve(sch)
+		if err != nil {
+			return nil
+		}
+		if item != nil {
+			t1, err1 := goType(item, idx)
+			t2, err2 := goType(p, idx)
+			if err1 != nil || err2 != nil || t1 != t2 {
+				return nil
+			}
+			continue
+		}
+		item = p
+	}
+	return item
+}
+
+// returns the type of a schema, ref for a schema with $ref which is generated as the schema it references
+func typeOf(s *jsonschema.Schema) string {
+	if s.IsRef() {
+		return "ref"
+	}
+	return s.Type
+}
+
+// returns true if a named type with a Validate() method is generated for a schema
+func isNamedType(s *jsonschema.Schema, idx *jsonschema.Index) bool {
+	return isNamedTypeOf(s, idx, map[*jsonschema.Schema]bool{})
+}
+
+// isNamedType of a schema reached through the variants of the unions seen
+func isNamedTypeOf(s *jsonschema.Schema, idx *jsonschema.Index, seen map[*jsonschema.Schema]bool) bool {
+	return typeOf(s) == "object" || typeOf(s) == "array" || enumGoType(s) != "" || len(goUnionVariantsOf(s, idx, seen)) > 0 ||
+		len(goTypeUnionTypes(s)) > 0
+}
+
+// returns true if a plain struct type without JSON funcs is generated for a schema
+func isStructType(s *jsonschema.Schema, idx *jsonschema.Index) bool {
+	if typeOf(s) != "object" || enumGoType(s) != "" || len(goUnionVariants(s, idx)) > 0 {
+		return false
 	}
-	sort.Sort(byName(schemas))
+	vt, _, err := goMapValueType(s, idx)
+	return err == nil && vt == ""
+}
+
+// returns true if a map type is generated for an object schema
+func isMapType(s *jsonschema.Schema, idx *jsonschema.Index) bool {
+	if typeOf(s) != "object" || len(s.Properties) > 0 || len(s.AllOf) > 0 {
+		return false
+	}
+	vt, _, err := goMapValueType(s, idx)
+	return err == nil && vt != ""
+}
+
+// returns the go type of the patternProperties and additionalProperties values of an object schema,
+// "" if they are not kept, and their schema if all values are of the same named type
+func goMapValueType(s *jsonschema.Schema, idx *jsonschema.Index) (string, *jsonschema.Schema, error) {
+	var schemas []*jsonschema.Schema
+	for _, k := range sortedMapKeys(s.PatternProperties) {
+		schemas = append(schemas, s.PatternProperties[k])
+	}
+	if ap := s.AdditionalProperties; ap != nil && ap.Boolean == nil {
+		schemas = append(schemas, ap)
+	} else if ap != nil && *ap.Boolean && len(s.Properties) == 0 && len(s.AllOf) == 0 {
+		// additionalProperties true of an object without properties is a map of any values
+		schemas = append(schemas, ap)
+	}
+
+	typ := ""
+	var named *jsonschema.Schema
+	for _, sch := range schemas {
+		p, err := idx.Resolve(sch)
+		if err != nil {
+			return "", nil, err
+		}
+		t, err := goType(p, idx)
+		if err != nil {
+			return "", nil, err
+		}
+		if t == "" {
+			t = "interface{}"
+		}
+		if typ != "" && typ != t {
+			return "interface{}", nil, nil
+		}
+		typ = t
+		if isNamedType(p, idx) {
+			named = p
+		}
+	}
+	return typ, named, nil
+}
+
+// returns the go type of an enum or const schema, or "" if the schema is none or has values of mixed types
+func enumGoType(s *jsonschema.Schema) string {
+	values := s.Enum
+	if s.HasConst() {
+		values = []interface{}{s.Const}
+	}
+	if len(values) == 0 {
+		return ""
+	}
+
+	typ := typeOf(s)
+	n := 0
+	for _, v := range values {
+		var vt string
+		switch t := v.(type) {
+		case nil:
+			continue
+		case string:
+			vt = "string"
+		case bool:
+			vt = "boolean"
+		case float64:
+			vt = "number"
+			if typ == "integer" && t == math.Trunc(t) {
+				vt = "integer"
+			}
+		case json.Number:
+			vt = "number"
+			if _, ok := intOf(t); ok && typ == "integer" {
+				vt = "integer"
+			}
+		}
+		if typ == "" {
+			typ = vt
+		}
+		if typ != vt {
+			return ""
+		}
+		n++
+	}
+	if n == 0 {
+		return ""
+	}
+
+	switch typ {
+	case "string":
+		return "string"
+	case "integer":
+		return "int"
+	case "number":
+		return "float64"
+	case "boolean":
+		return "bool"
+	}
+	return ""
+}
+
+// a generated constant of an enum type
+type goEnumValue struct {
+	Name    string
+	Literal string
+	Value   interface{}
+}
+
+// returns the unique non-null values of an enum or const schema as constants
+func goEnumValues(s *jsonschema.Schema) []goEnumValue {
+	values := s.Enum
+	if s.HasConst() {
+		values = []interface{}{s.Const}
+	}
+
+	var consts []goEnumValue
+	names := map[string]bool{}
+	literals := map[string]bool{}
+	for _, v := range values {
+		var name, literal string
+		switch t := v.(type) {
+		case string:
+			name = goNameFromStrings(regexp.MustCompile(`[^\pL\pN]+`).Split(t, -1)...)
+			literal = fmt.Sprintf("%q", t)
+		case float64:
+			literal = strconv.FormatFloat(t, 'f', -1, 64)
+			name = strings.NewReplacer("-", "Minus", ".", "_").Replace(literal)
+		case json.Number:
+			// integers keep all digits, other numbers are written as their float64
+			if i, ok := new(big.Int).SetString(string(t), 10); ok {
+				literal = i.String()
+			} else {
+				f, _ := t.Float64()
+				literal = strconv.FormatFloat(f, 'f', -1, 64)
+			}
+			name = strings.NewReplacer("-", "Minus", ".", "_").Replace(literal)
+		case bool:
+			literal = fmt.Sprintf("%v", t)
+			name = strings.Title(literal)
+		default:
+			continue
+		}
+		if literals[literal] {
+			continue
+		}
+		literals[literal] = true
+
+		if name == "" {
+			name = "Empty"
+		}
+		name = s.Name + name
+		for i, base := 2, name; names[name]; i++ {
+			name = fmt.Sprintf("%v%v", base, i)
+		}
+		names[name] = true
+
+		consts = append(consts, goEnumValue{Name: name, Literal: literal, Value: v})
+	}
+	return consts
+}
+
+// Generates the formatted go validate funcs for all types in the index
+func generateGoTypesValidateFuncs(idx *jsonschema.Index, opts Options) ([]byte, error) {
+	w := bytes.NewBufferString("\n")
+	for _, k := range sortedMapKeysbyName(idx) {
+		s, _ := idx.Get(k)
+		t, err := generateGoTypeValidateFunc(s, idx, opts)
+		if err != nil {
+			return nil, err
+		}
+		if string(t) != "" {
+			fmt.Fprintf(w, "%s\n", t)
+		}
+	}
+
+	return format.Source(w.Bytes())
+}
+
+// Generates the validate func for a schema
+func generateGoTypeValidateFunc(s *jsonschema.Schema, idx *jsonschema.Index, opts Options) ([]byte, error) {
+	w := &validateFuncWriter{opts: opts}
+	fmt.Fprintf(w, "func (t *%v) Validate() error {\n", s.Name)
+	if opts.AllErrors {
+		fmt.Fprintf(w, "\terrs := ValidationErrors{}\n")
+	}
+	switch {
+	case enumGoType(s) != "":
+		generateEnumValidationCheck(w, s)
+	case len(goUnionVariants(s, idx)) > 0:
+		generateUnionValidationCheck(w, s, idx)
+	case len(goTypeUnionTypes(s)) > 0:
+		err := generateTypeUnionValidationCheck(w, s)
+		if err != nil {
+			return nil, err
+		}
+	case isMapType(s, idx):
+		err := generateObjectValidationChecks(w, s, idx)
+		if err != nil {
+			return nil, err
+		}
+
+		_, vs, err := goMapValueType(s, idx)
+		if err != nil {
+			return nil, err
+		}
+		if vs != nil {
+			generateMapValidateCalls(w, "*t")
+		}
+	case typeOf(s) == "object":
+		err := generateRequiredValidationCheck(w, idx, s)
+		if err != nil {
+			return nil, err
+		}
+
+		err = generateObjectValidationChecks(w, s, idx)
+		if err != nil {
+			return nil, err
+		}
+
+		err = generateConditionalValidationChecks(w, s, idx)
+		if err != nil {
+			return nil, err
+		}
+
+		// constraint checks of primitive type properties
+		for _, k := range sortedMapKeys(s.Properties) {
+			err := generatePropertyConstraintChecks(w, s, s.Properties[k], idx)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		// Validate() calls of embedded allOf types
+		for _, a := range s.AllOf {
+			p, err := idx.Resolve(a)
+			if err != nil {
+				return nil, err
+			}
+			if isStructType(p, idx) {
+				fmt.Fprintf(w, "\tif err := t.%v.Validate(); err != nil {\n", p.Name)
+				w.failNested("err", `""`)
+				fmt.Fprintf(w, "\t}\n")
+			}
+		}
+
+		// Validate() calls of non-primitive type properties
+		for _, k := range sortedMapKeys(s.Properties) {
+			p, err := idx.Resolve(s.Properties[k])
+			if err != nil {
+				return nil, err
+			}
+
+			if isNamedType(p, idx) {
+				f := goFieldOf(s.Properties[k], idx)
+				fmt.Fprintf(w, "\tif %v {\n", f.Valid)
+				fmt.Fprintf(w, "\t\terr := %v.Validate()\n", f.Ref)
+				fmt.Fprintf(w, "\t\tif err != nil {\n")
+				w.failNested("err", fmt.Sprintf("%q", "/"+k))
+				fmt.Fprintf(w, "\t\t}\n")
+				fmt.Fprintf(w, "\t}\n")
+			}
+		}
+
+		// Validate() calls of additional properties
+		_, vs, err := goMapValueType(s, idx)
+		if err != nil {
+			return nil, err
+		}
+		if vs != nil {
+			generateMapValidateCalls(w, "t.AdditionalProperties")
+		}
+	case typeOf(s) == "array":
+		err := generateArrayValidationChecks(w, s, idx)
+		if err != nil {
+			return nil, err
+		}
+
+		if as := goItemSchema(s, idx); as != nil && isNamedType(as, idx) {
+			fmt.Fprintf(w, "\tfor i := range *t {\n")
+			fmt.Fprintf(w, "\t\terr := (*t)[i].Validate()\n")
+			fmt.Fprintf(w, "\t\tif err != nil {\n")
+			w.failNested("err", "\"/\"+strconv.Itoa(i)")
+			fmt.Fprintf(w, "\t\t}\n")
+			fmt.Fprintf(w, "\t}\n")
+		}
+	default:
+		return nil, nil
+	}
+	if opts.AllErrors {
+		fmt.Fprintf(w, "\tif len(errs) > 0 {\n")
+		fmt.Fprintf(w, "\t\treturn errs\n")
+		fmt.Fprintf(w, "\t}\n")
+	}
+	fmt.Fprintf(w, "\treturn nil\n")
+	fmt.Fprintf(w, "}\n")
+
+	return format.Source(append(w.decls.Bytes(), w.Bytes()...))
+}
+
+// generate "required" validation check
+func generateRequiredValidationCheck(w *validateFuncWriter, idx *jsonschema.Index, s *jsonschema.Schema) error {
+	// required names are fields of the type, of embedded allOf types or keys of additional properties
+	props := goObjectProperties(s, idx)
+	for k, prop := range s.Properties {
+		if _, ok := props[k]; !ok {
+			props[k] = prop
+		}
+	}
+	m, err := goPropertiesMap(s, idx)
+	if err != nil {
+		return err
+	}
+	for _, p := range s.Required {
+		check := goPresenceCheck(props, m, p, false, idx)
+		if check == "" {
+			return s.Errorf("required property %v is not defined in properties of %v", p, s.Pointer)
+		}
+		fmt.Fprintf(w, "if %v {\n", check)
+		w.fail(generateValidationError(instancePointerOf(p), s.Pointer, "required", fmt.Sprintf("invalid %v: missing %v", s.JSONName, p)))
+		fmt.Fprintf(w, "}\n")
+	}
+	if len(s.Required) > 0 {
+		fmt.Fprintf(w, "\n")
+	}
+	return nil
+}
+
+// generate numeric and string validation checks of a primitive type property
+func generatePropertyConstraintChecks(w *validateFuncWriter, s *jsonschema.Schema, prop *jsonschema.Schema, idx *jsonschema.Index) error {
+	p, err := idx.Resolve(prop)
+	if err != nil {
+		return err
+	}
+	if isNamedType(p, idx) {
+		return nil
+	}
+	typ, err := goType(p, idx)
+	if err != nil {
+		return err
+	}
+	f := goFieldOf(prop, idx)
+	return generateFieldChecks(w, w, f.Valid, prop.JSONName, p, f.Value, typ, "pattern"+s.Name+prop.Name)
+}
+
+// generate the validation checks of the keywords of p for the value v of a field into body if valid holds,
+// declaring a compiled pattern as variable re
+func generateFieldChecks(w *validateFuncWriter, body *validateFuncWriter, valid, jsonName string, p *jsonschema.Schema, v, typ, re string) error {
+	checks := &bytes.Buffer{}
+	err := generateValueChecks(w, p, v, typ, re, func(cond, keyword, msg string) {
+		fmt.Fprintf(checks, "if %v {\n", cond)
+		chk := &validateFuncWriter{opts: w.opts}
+		chk.fail(generateValidationError(instancePointerOf(jsonName), p.Pointer, keyword, fmt.Sprintf("invalid %v: %v", jsonName, msg)))
+		fmt.Fprintf(checks, "%s}\n", chk.Bytes())
+	})
+	if err != nil {
+		return err
+	}
+
+	if checks.Len() > 0 {
+		fmt.Fprintf(body, "if %v {\n", valid)
+		fmt.Fprintf(body, "%s", checks.Bytes())
+		fmt.Fprintf(body, "}\n")
+	}
+	return nil
+}
+
+// calls check with the violation condition of each numeric, string, enum and const keyword of p
+// for the value v of go type typ, declaring a compiled pattern as variable re
+func generateValueChecks(w *validateFuncWriter, p *jsonschema.Schema, v, typ, re string, check func(cond, keyword, msg string)) error {
+	switch typ {
+	case "int", "float64":
+		// compares ints to integral bounds as exact int constants, other bounds as float64
+		compare := func(n json.Number, op string) string {
+			if typ == "int" {
+				if i, ok := intOf(n); ok && isIntConstant(i) {
+					return fmt.Sprintf("%v %v %v", v, op, i)
+				} else if ok && i.IsInt64() {
+					return fmt.Sprintf("int64(%v) %v %v", v, op, i)
+				}
+				return fmt.Sprintf("float64(%v) %v %v", v, op, formatNumber(n))
+			}
+			return fmt.Sprintf("%v %v %v", v, op, formatNumber(n))
+		}
+		if m, ok := p.Number("multipleOf"); ok {
+			msg := "must be a multiple of " + formatNumber(m)
+			i, isInt := intOf(m)
+			switch {
+			case typ == "int" && isInt && isIntConstant(i):
+				check(fmt.Sprintf("%v%%%v != 0", v, i), "multipleOf", msg)
+			case typ == "int" && isInt && i.IsInt64():
+				check(fmt.Sprintf("int64(%v)%%%v != 0", v, i), "multipleOf", msg)
+			case typ == "int" && isInt:
+				// only 0 is a multiple of an integer beyond int64
+				check(fmt.Sprintf("%v != 0", v), "multipleOf", msg)
+			default:
+				fv := v
+				if typ == "int" {
+					fv = "float64(" + v + ")"
+				}
+				check(fmt.Sprintf("q := %v / %v; math.Abs(q-math.Floor(q+0.5)) > 1e-9", fv, formatNumber(m)), "multipleOf", msg)
+			}
+		}
+		if b, ok := p.Number("maximum"); ok {
+			check(compare(b, ">"), "maximum", "must be <= "+formatNumber(b))
+		}
+		if b, ok := p.Number("exclusiveMaximum"); ok {
+			check(compare(b, ">="), "exclusiveMaximum", "must be < "+formatNumber(b))
+		}
+		if b, ok := p.Number("minimum"); ok {
+			check(compare(b, "<"), "minimum", "must be >= "+formatNumber(b))
+		}
+		if b, ok := p.Number("exclusiveMinimum"); ok {
+			check(compare(b, "<="), "exclusiveMinimum", "must be > "+formatNumber(b))
+		}
+	case "string":
+		err := generateStringChecks(w, p, v, re, check)
+		if err != nil {
+			return err
+		}
+	}
+
+	// enum and const values of the same type are compared with their literals
+	if et := enumGoType(p); et != "" && (et == typ || et == "int" && typ == "float64") {
+		var conds, values []string
+		for _, ev := range goEnumValues(p) {
+			conds = append(conds, fmt.Sprintf("%v != %v", v, ev.Literal))
+			values = append(values, fmt.Sprintf("%v", ev.Value))
+		}
+		if isNullableEnum(p) {
+			values = append(values, "null")
+		}
+		if p.HasConst() {
+			check(strings.Join(conds, " && "), "const", "must be "+strings.Join(values, ", "))
+		} else {
+			check(strings.Join(conds, " && "), "enum", "must be one of "+strings.Join(values, ", "))
+		}
+	}
+	return nil
+}
+
+// generate "maxLength", "minLength" and "pattern" conditions of a string value v,
+// declaring the compiled pattern as variable re
+func generateStringChecks(w *validateFuncWriter, p *jsonschema.Schema, v, re string, check func(cond, keyword, msg string)) error {
+	if n := p.MaxLength; n != nil {
+		check(fmt.Sprintf("utf8.RuneCountInString(%v) > %v", v, *n), "maxLength", fmt.Sprintf("must be at most %v characters long", *n))
+	}
+	if n := p.MinLength; n != nil {
+		check(fmt.Sprintf("utf8.RuneCountInString(%v) < %v", v, *n), "minLength", fmt.Sprintf("must be at least %v characters long", *n))
+	}
+	if p.Pattern != "" {
+		_, err := regexp.Compile(p.Pattern)
+		if err != nil {
+			return p.Errorf("invalid pattern %v of %v: %v", p.Pattern, p.Pointer, err)
+		}
+		fmt.Fprintf(&w.decls, "var %v = regexp.MustCompile(%q)\n\n", re, p.Pattern)
+		check(fmt.Sprintf("!%v.MatchString(%v)", re, v), "pattern", "must match "+p.Pattern)
+	}
+	return nil
+}
+
+// generate "maxProperties", "minProperties", "propertyNames", "dependentRequired" validation checks
+// and the required checks of "dependentSchemas" of an object or map type
+func generateObjectValidationChecks(w *validateFuncWriter, s *jsonschema.Schema, idx *jsonschema.Index) error {
+	props := goObjectProperties(s, idx)
+	m, err := goPropertiesMap(s, idx)
+	if err != nil {
+		return err
+	}
+
+	if s.MaxProperties != nil || s.MinProperties != nil {
+		if m != "" {
+			fmt.Fprintf(w, "props := len(%v)\n", m)
+		} else {
+			fmt.Fprintf(w, "props := 0\n")
+		}
+		var names []string
+		for k := range props {
+			names = append(names, k)
+		}
+		sort.Strings(names)
+		for _, k := range names {
+			fmt.Fprintf(w, "if %v {\n", goFieldOf(props[k], idx).Set)
+			fmt.Fprintf(w, "\tprops++\n")
+			fmt.Fprintf(w, "}\n")
+		}
+		if n := s.MaxProperties; n != nil {
+			fmt.Fprintf(w, "if props > %v {\n", *n)
+			w.fail(generateValidationError("", s.Pointer, "maxProperties", fmt.Sprintf("invalid %v: must have at most %v properties", s.JSONName, *n)))
+			fmt.Fprintf(w, "}\n")
+		}
+		if n := s.MinProperties; n != nil {
+			fmt.Fprintf(w, "if props < %v {\n", *n)
+			w.fail(generateValidationError("", s.Pointer, "minProperties", fmt.Sprintf("invalid %v: must have at least %v properties", s.JSONName, *n)))
+			fmt.Fprintf(w, "}\n")
+		}
+	}
+
+	if s.PropertyNames != nil && m != "" {
+		pn, err := idx.Resolve(s.PropertyNames)
+		if err != nil {
+			return err
+		}
+		var conds, reqs []string
+		err = generateStringChecks(w, pn, "k", "pattern"+pn.Name, func(cond, keyword, msg string) {
+			conds = append(conds, cond)
+			reqs = append(reqs, strings.TrimPrefix(msg, "must "))
+		})
+		if err != nil {
+			return err
+		}
+		if len(conds) > 0 {
+			fmt.Fprintf(w, "names := true\n")
+			fmt.Fprintf(w, "for k := range %v {\n", m)
+			fmt.Fprintf(w, "\tif %v {\n", strings.Join(conds, " || "))
+			fmt.Fprintf(w, "\t\tnames = false\n")
+			fmt.Fprintf(w, "\t}\n")
+			fmt.Fprintf(w, "}\n")
+			fmt.Fprintf(w, "if !names {\n")
+			w.fail(generateValidationError("", pn.Pointer, "propertyNames", fmt.Sprintf("invalid %v: property names must %v", s.JSONName, strings.Join(reqs, " and "))))
+			fmt.Fprintf(w, "}\n")
+		}
+	}
+
+	// required properties by the presence of a property
+	type dependency struct {
+		required []string
+		schema   *jsonschema.Schema
+		keyword  string
+	}
+	deps := map[string][]dependency{}
+	for name, required := range s.DependentRequired {
+		deps[name] = append(deps[name], dependency{required, s, "dependentRequired"})
+	}
+	for _, dss := range []jsonschema.Schemas{s.DependentSchemas, s.Dependencies} {
+		for name, ds := range dss {
+			p, err := idx.Resolve(ds)
+			if err != nil {
+				return err
+			}
+			deps[name] = append(deps[name], dependency{p.Required, p, "required"})
+		}
+	}
+
+	var names []string
+	for name := range deps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if goPresenceCheck(props, m, name, true, idx) == "" {
+			continue
+		}
+		checks := &validateFuncWriter{opts: w.opts}
+		for _, d := range deps[name] {
+			for _, r := range d.required {
+				if goPresenceCheck(props, m, r, false, idx) == "" {
+					continue
+				}
+				fmt.Fprintf(checks, "if %v {\n", goPresenceCheck(props, m, r, false, idx))
+				checks.fail(generateValidationError(instancePointerOf(r), d.schema.Pointer, d.keyword, fmt.Sprintf("invalid %v: missing %v required by %v", s.JSONName, r, name)))
+				fmt.Fprintf(checks, "}\n")
+			}
+		}
+		if checks.Len() > 0 {
+			fmt.Fprintf(w, "if %v {\n", goPresenceCheck(props, m, name, true, idx))
+			fmt.Fprintf(w, "%s", checks.Bytes())
+			fmt.Fprintf(w, "}\n")
+		}
+	}
+	return nil
+}
+
+// returns the property schemas with a field of an object type including embedded structs by JSON name
+func goObjectProperties(s *jsonschema.Schema, idx *jsonschema.Index) map[string]*jsonschema.Schema {
+	props := map[string]*jsonschema.Schema{}
+	for _, a := range s.AllOf {
+		p, err := idx.Resolve(a)
+		if err == nil && isStructType(p, idx) {
+			for k, v := range goObjectProperties(p, idx) {
+				props[k] = v
+			}
+		}
+	}
+	for k, prop := range s.Properties {
+		if typ, err := goType(prop, idx); err == nil && (typ != "" || goUntypedType(prop, idx) != "") {
+			props[k] = prop
+		}
+	}
+	return props
+}
+
+// returns the go expression of the map holding the properties without field of an object type, "" if there is none
+func goPropertiesMap(s *jsonschema.Schema, idx *jsonschema.Index) (string, error) {
+	vt, _, err := goMapValueType(s, idx)
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case isMapType(s, idx):
+		return "*t", nil
+	case vt != "":
+		return "t.AdditionalProperties", nil
+	}
+	return "", nil
+}
+
+// returns the if statement header checking the presence (ok) or absence of a property, "" if not expressible
+func goPresenceCheck(props map[string]*jsonschema.Schema, m string, name string, ok bool, idx *jsonschema.Index) string {
+	if prop, found := props[name]; found {
+		if ok {
+			return goFieldOf(prop, idx).Set
+		}
+		return goFieldOf(prop, idx).Unset
+	}
+	if m == "" {
+		return ""
+	}
+	if ok {
+		return fmt.Sprintf("_, ok := (%v)[%q]; ok", m, name)
+	}
+	return fmt.Sprintf("_, ok := (%v)[%q]; !ok", m, name)
+}
+
+// generate "if", "then" and "else" validation checks of an object type.
+// The if schema is evaluated by a generated helper method against the fields of the type.
+func generateConditionalValidationChecks(w *validateFuncWriter, s *jsonschema.Schema, idx *jsonschema.Index) error {
+	if s.If == nil || (s.Then == nil && s.Else == nil) {
+		return nil
+	}
+	cond, err := idx.Resolve(s.If)
+	if err != nil {
+		return err
+	}
+
+	then := &validateFuncWriter{opts: w.opts}
+	if s.Then != nil {
+		err := generateBranchChecks(w, then, s, s.Then, idx)
+		if err != nil {
+			return err
+		}
+	}
+	els := &validateFuncWriter{opts: w.opts}
+	if s.Else != nil {
+		err := generateBranchChecks(w, els, s, s.Else, idx)
+		if err != nil {
+			return err
+		}
+	}
+	if then.Len() == 0 && els.Len() == 0 {
+		return nil
+	}
+
+	// helper evaluating the if schema, conditions it cannot evaluate are left to runtime validation
+	helper := "matches" + cond.Name
+	props := goObjectProperties(s, idx)
+	m, err := goPropertiesMap(s, idx)
+	if err != nil {
+		return err
+	}
+	if !isGoCondition(cond, props, m, idx) {
+		return nil
+	}
+	h := &bytes.Buffer{}
+	fmt.Fprintf(h, "// %v reports whether t is valid against %v\n", helper, cond.Pointer)
+	fmt.Fprintf(h, "func (t *%v) %v() bool {\n", s.Name, helper)
+	for _, r := range cond.Required {
+		fmt.Fprintf(h, "if %v {\n", goPresenceCheck(props, m, r, false, idx))
+		fmt.Fprintf(h, "\treturn false\n")
+		fmt.Fprintf(h, "}\n")
+	}
+	for _, k := range sortedMapKeys(cond.Properties) {
+		prop, ok := props[k]
+		if !ok {
+			continue
+		}
+		p, err := idx.Resolve(cond.Properties[k])
+		if err != nil {
+			return err
+		}
+		v, typ, err := goFieldValue(prop, idx)
+		if err != nil {
+			return err
+		}
+		checks := &bytes.Buffer{}
+		err = generateValueChecks(w, p, v, typ, "pattern"+s.Name+"If"+prop.Name, func(c, keyword, msg string) {
+			fmt.Fprintf(checks, "if %v {\n", c)
+			fmt.Fprintf(checks, "\treturn false\n")
+			fmt.Fprintf(checks, "}\n")
+		})
+		if err != nil {
+			return err
+		}
+		if checks.Len() > 0 {
+			fmt.Fprintf(h, "if %v {\n", goFieldOf(prop, idx).Valid)
+			fmt.Fprintf(h, "%s", checks.Bytes())
+			fmt.Fprintf(h, "}\n")
+		}
+	}
+	fmt.Fprintf(h, "return true\n")
+	fmt.Fprintf(h, "}\n\n")
+	fmt.Fprintf(&w.decls, "%s", h.Bytes())
+
+	switch {
+	case els.Len() == 0:
+		fmt.Fprintf(w, "if t.%v() {\n", helper)
+		fmt.Fprintf(w, "%s", then.Bytes())
+	case then.Len() == 0:
+		fmt.Fprintf(w, "if !t.%v() {\n", helper)
+		fmt.Fprintf(w, "%s", els.Bytes())
+	default:
+		fmt.Fprintf(w, "if t.%v() {\n", helper)
+		fmt.Fprintf(w, "%s", then.Bytes())
+		fmt.Fprintf(w, "} else {\n")
+		fmt.Fprintf(w, "%s", els.Bytes())
+	}
+	fmt.Fprintf(w, "}\n")
+	return nil
+}
+
+// reports whether the generated helper of an if schema evaluates all its keywords against the fields props
+// and the additional properties m of an object type
+func isGoCondition(cond *jsonschema.Schema, props map[string]*jsonschema.Schema, m string, idx *jsonschema.Index) bool {
+	if cond.Boolean != nil {
+		return *cond.Boolean
+	}
+	for _, kw := range cond.Keywords() {
+		switch {
+		case kw == "required" || kw == "properties" || isGoAnnotationKeyword(kw):
+		case kw == "type":
+			if len(cond.Types) != 1 || cond.Types[0] != "object" {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	for _, r := range cond.Required {
+		if goPresenceCheck(props, m, r, false, idx) == "" {
+			return false
+		}
+	}
+	for k, cp := range cond.Properties {
+		prop, ok := props[k]
+		if !ok {
+			// properties without field are only absent if there are no additional properties
+			if m != "" {
+				return false
+			}
+			continue
+		}
+		p, err := idx.Resolve(cp)
+		if err != nil {
+			return false
+		}
+		_, typ, err := goFieldValue(prop, idx)
+		if err != nil || !isGoValueCondition(p, typ, isNullableField(prop, idx)) {
+			return false
+		}
+	}
+	return true
+}
+
+// reports whether generateValueChecks evaluates all keywords of a property schema p of an if schema
+// for a field value of go type typ
+func isGoValueCondition(p *jsonschema.Schema, typ string, nullable bool) bool {
+	if p.Boolean != nil {
+		return *p.Boolean
+	}
+	for _, kw := range p.Keywords() {
+		switch kw {
+		case "type":
+			// the field type has to be the only type besides null, which has to be allowed for nullable fields
+			null := false
+			for _, t := range p.Types {
+				switch {
+				case t == "null":
+					null = true
+				case !(t == "integer" && typ == "int" || t == "number" && (typ == "int" || typ == "float64") ||
+					t == "string" && typ == "string" || t == "boolean" && typ == "bool"):
+					return false
+				}
+			}
+			if len(p.Types) == 0 || nullable && !null || null && len(p.Types) == 1 {
+				return false
+			}
+		case "multipleOf", "maximum", "exclusiveMaximum", "minimum", "exclusiveMinimum":
+			if typ != "int" && typ != "float64" {
+				return false
+			}
+		case "maxLength", "minLength", "pattern":
+			if typ != "string" {
+				return false
+			}
+		case "enum", "const":
+			et := enumGoType(p)
+			if nullable || et == "" || et != typ && !(et == "int" && typ == "float64") {
+				return false
+			}
+		default:
+			if !isGoAnnotationKeyword(kw) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// reports whether a keyword does not affect validation
+func isGoAnnotationKeyword(kw string) bool {
+	switch kw {
+	case "$schema", "$id", "$anchor", "$comment", "$defs", "definitions", "title", "description", "default",
+		"examples", "deprecated", "readOnly", "writeOnly", "format":
+		return true
+	}
+	return false
+}
+
+// generate the required and property checks of a then or else branch of an object type into body.
+// Patterns are named after the type and branch, as the branch may reference a type with patterns of its own.
+func generateBranchChecks(w *validateFuncWriter, body *validateFuncWriter, s *jsonschema.Schema, branch *jsonschema.Schema, idx *jsonschema.Index) error {
+	b, err := idx.Resolve(branch)
+	if err != nil {
+		return err
+	}
+	name := s.Name + "Else"
+	if branch == s.Then {
+		name = s.Name + "Then"
+	}
+	props := goObjectProperties(s, idx)
+	m, err := goPropertiesMap(s, idx)
+	if err != nil {
+		return err
+	}
+
+	for _, r := range b.Required {
+		pc := goPresenceCheck(props, m, r, false, idx)
+		if pc == "" {
+			continue
+		}
+		fmt.Fprintf(body, "if %v {\n", pc)
+		body.fail(generateValidationError(instancePointerOf(r), b.Pointer, "required", fmt.Sprintf("invalid %v: missing %v", s.JSONName, r)))
+		fmt.Fprintf(body, "}\n")
+	}
+
+	for _, k := range sortedMapKeys(b.Properties) {
+		prop, ok := props[k]
+		if !ok {
+			continue
+		}
+		p, err := idx.Resolve(b.Properties[k])
+		if err != nil {
+			return err
+		}
+		v, typ, err := goFieldValue(prop, idx)
+		if err != nil {
+			return err
+		}
+		err = generateFieldChecks(w, body, goFieldOf(prop, idx).Valid, k, p, v, typ, "pattern"+name+prop.Name)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// returns the go expression of the value of the field of a primitive or enum property and its underlying go type,
+// "" if the field is of another type
+func goFieldValue(prop *jsonschema.Schema, idx *jsonschema.Index) (string, string, error) {
+	p, err := idx.Resolve(prop)
+	if err != nil {
+		return "", "", err
+	}
+	v := goFieldOf(prop, idx).Value
+	if typ := enumGoType(p); typ != "" {
+		return typ + "(" + v + ")", typ, nil
+	}
+	if isNamedType(p, idx) {
+		return "", "", nil
+	}
+	typ, err := goType(p, idx)
+	if err != nil {
+		return "", "", err
+	}
+	return v, typ, nil
+}
+
+// formats a number as go literal, integers with all their digits
+func formatNumber(n json.Number) string {
+	if i, ok := new(big.Int).SetString(string(n), 10); ok {
+		return new(big.Float).SetInt(i).Text('g', -1)
+	}
+	f, _ := n.Float64()
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// returns the exact integer of a number, false if it is not integral
+func intOf(n json.Number) (*big.Int, bool) {
+	if i, ok := new(big.Int).SetString(string(n), 10); ok {
+		return i, true
+	}
+	f, err := n.Float64()
+	if err != nil || f != math.Trunc(f) || math.IsInf(f, 0) {
+		return nil, false
+	}
+	i, _ := big.NewFloat(f).Int(nil)
+	return i, true
+}
+
+// reports whether an integer compiles as an int constant on all platforms, i.e. fits into 32 bits
+func isIntConstant(i *big.Int) bool {
+	return i.IsInt64() && i.Int64() >= math.MinInt32 && i.Int64() <= math.MaxInt32
+}
+
+// generate "minItems", "maxItems", "uniqueItems" and "contains" validation checks of an array type
+func generateArrayValidationChecks(w *validateFuncWriter, s *jsonschema.Schema, idx *jsonschema.Index) error {
+	if n := s.MaxItems; n != nil {
+		fmt.Fprintf(w, "if len(*t) > %v {\n", *n)
+		w.fail(generateValidationError("", s.Pointer, "maxItems", fmt.Sprintf("invalid %v: must have at most %v items", s.JSONName, *n)))
+		fmt.Fprintf(w, "}\n")
+	}
+	if prefix, rest := s.TupleItems(); rest != nil && rest.Boolean != nil && !*rest.Boolean {
+		// items of a false schema are not allowed after the tuple items
+		fmt.Fprintf(w, "if len(*t) > %v {\n", len(prefix))
+		w.fail(generateValidationError(fmt.Sprintf("/%v", len(prefix)), rest.Pointer, "false", fmt.Sprintf("invalid %v: must have at most %v items", s.JSONName, len(prefix))))
+		fmt.Fprintf(w, "}\n")
+	}
+	if n := s.MinItems; n != nil {
+		fmt.Fprintf(w, "if len(*t) < %v {\n", *n)
+		w.fail(generateValidationError("", s.Pointer, "minItems", fmt.Sprintf("invalid %v: must have at least %v items", s.JSONName, *n)))
+		fmt.Fprintf(w, "}\n")
+	}
+
+	if s.UniqueItems {
+		equal := "reflect.DeepEqual((*t)[i], (*t)[j])"
+		if isComparableType(goItemSchema(s, idx), idx) {
+			equal = "(*t)[i] == (*t)[j]"
+		}
+		fmt.Fprintf(w, "unique := true\n")
+		fmt.Fprintf(w, "for j := range *t {\n")
+		fmt.Fprintf(w, "\tfor i := 0; i < j; i++ {\n")
+		fmt.Fprintf(w, "\t\tif %v {\n", equal)
+		fmt.Fprintf(w, "\t\t\tunique = false\n")
+		fmt.Fprintf(w, "\t\t}\n")
+		fmt.Fprintf(w, "\t}\n")
+		fmt.Fprintf(w, "}\n")
+		fmt.Fprintf(w, "if !unique {\n")
+		w.fail(generateValidationError("", s.Pointer, "uniqueItems", fmt.Sprintf("invalid %v: items must be unique", s.JSONName)))
+		fmt.Fprintf(w, "}\n")
+	}
+
+	if s.Contains == nil {
+		return nil
+	}
+	cs, err := idx.Resolve(s.Contains)
+	if err != nil {
+		return err
+	}
+	is := goItemSchema(s, idx)
+
+	if !isNamedType(cs, idx) {
+		// items of primitive types match if no value check of the contains schema fails
+		v, typ, err := goItemValue(is, idx)
+		if err != nil {
+			return err
+		}
+		if typ == "" || !isGoValueCondition(cs, typ, false) {
+			// other contains schemas are enforced by runtime validation only
+			return nil
+		}
+		checks := &bytes.Buffer{}
+		err = generateValueChecks(w, cs, v, typ, "pattern"+s.Name+"Contains", func(cond, keyword, msg string) {
+			fmt.Fprintf(checks, "\tif %v {\n", cond)
+			fmt.Fprintf(checks, "\t\tcontinue\n")
+			fmt.Fprintf(checks, "\t}\n")
+		})
+		if err != nil {
+			return err
+		}
+		if checks.Len() == 0 {
+			fmt.Fprintf(w, "n := len(*t)\n")
+		} else {
+			fmt.Fprintf(w, "n := 0\n")
+			fmt.Fprintf(w, "for i := range *t {\n")
+			fmt.Fprintf(w, "%s", checks.Bytes())
+			fmt.Fprintf(w, "\tn++\n")
+			fmt.Fprintf(w, "}\n")
+		}
+		generateContainsCountChecks(w, s)
+		return nil
+	}
+
+	fmt.Fprintf(w, "n := 0\n")
+	fmt.Fprintf(w, "for i := range *t {\n")
+	if is == cs {
+		fmt.Fprintf(w, "\tif (*t)[i].Validate() == nil {\n")
+	} else {
+		// items are converted to the contains type through their JSON encoding
+		fmt.Fprintf(w, "\tvar c %v\n", cs.Name)
+		fmt.Fprintf(w, "\tb, err := json.Marshal((*t)[i])\n")
+		fmt.Fprintf(w, "\tif err == nil && json.Unmarshal(b, &c) == nil && c.Validate() == nil {\n")
+	}
+	fmt.Fprintf(w, "\t\tn++\n")
+	fmt.Fprintf(w, "\t}\n")
+	fmt.Fprintf(w, "}\n")
+	generateContainsCountChecks(w, s)
+	return nil
+}
+
+// generate "contains", "minContains" and "maxContains" checks of the number n of matching items
+func generateContainsCountChecks(w *validateFuncWriter, s *jsonschema.Schema) {
+	min, keyword := 1, "contains"
+	if s.MinContains != nil {
+		min, keyword = *s.MinContains, "minContains"
+	}
+	if min > 0 {
+		fmt.Fprintf(w, "if n < %v {\n", min)
+		w.fail(generateValidationError("", s.Pointer, keyword, fmt.Sprintf("invalid %v: must contain at least %v matching items", s.JSONName, min)))
+		fmt.Fprintf(w, "}\n")
+	}
+	if n := s.MaxContains; n != nil {
+		fmt.Fprintf(w, "if n > %v {\n", *n)
+		w.fail(generateValidationError("", s.Pointer, "maxContains", fmt.Sprintf("invalid %v: must contain at most %v matching items", s.JSONName, *n)))
+		fmt.Fprintf(w, "}\n")
+	}
+}
+
+// returns the go expression of an item (*t)[i] of a primitive or enum item schema and its underlying go type,
+// "" if the items are of another type
+func goItemValue(is *jsonschema.Schema, idx *jsonschema.Index) (string, string, error) {
+	if is == nil {
+		return "", "", nil
+	}
+	p, err := idx.Resolve(is)
+	if err != nil {
+		return "", "", err
+	}
+	v := "(*t)[i]"
+	if typ := enumGoType(p); typ != "" {
+		return typ + "(" + v + ")", typ, nil
+	}
+	if isNamedType(p, idx) {
+		return "", "", nil
+	}
+	typ, err := goType(p, idx)
+	if err != nil {
+		return "", "", err
+	}
+	return v, typ, nil
+}
+
+// returns true if values of the go type of a schema can be compared with ==
+func isComparableType(s *jsonschema.Schema, idx *jsonschema.Index) bool {
+	if s == nil {
+		return false
+	}
+	p, err := idx.Resolve(s)
+	if err != nil {
+		return false
+	}
+	if enumGoType(p) != "" {
+		return true
+	}
+	switch p.Type {
+	case "string", "integer", "number", "boolean":
+		return true
+	}
+	return false
+}
+
+// generate Validate() calls of map values in key order
+func generateMapValidateCalls(w *validateFuncWriter, m string) {
+	fmt.Fprintf(w, "keys := make([]string, 0, len(%v))\n", m)
+	fmt.Fprintf(w, "for k := range %v {\n", m)
+	fmt.Fprintf(w, "\tkeys = append(keys, k)\n")
+	fmt.Fprintf(w, "}\n")
+	fmt.Fprintf(w, "sort.Strings(keys)\n")
+	fmt.Fprintf(w, "for _, k := range keys {\n")
+	fmt.Fprintf(w, "\tv := (%v)[k]\n", m)
+	fmt.Fprintf(w, "\terr := v.Validate()\n")
+	fmt.Fprintf(w, "\tif err != nil {\n")
+	w.failNested("err", `"/"+k`)
+	fmt.Fprintf(w, "\t}\n")
+	fmt.Fprintf(w, "}\n")
+}
+
+// generate "enum" or "const" validation check
+func generateEnumValidationCheck(w *validateFuncWriter, s *jsonschema.Schema) {
+	var names, values []string
+	for _, v := range goEnumValues(s) {
+		names = append(names, v.Name)
+		values = append(values, fmt.Sprintf("%v", v.Value))
+	}
+	if isNullableEnum(s) {
+		values = append(values, "null")
+	}
+
+	keyword := "enum"
+	msg := fmt.Sprintf("invalid %v: must be one of %v", s.JSONName, strings.Join(values, ", "))
+	if s.HasConst() {
+		keyword = "const"
+		msg = fmt.Sprintf("invalid %v: must be %v", s.JSONName, strings.Join(values, ", "))
+	}
+
+	fmt.Fprintf(w, "switch *t {\n")
+	fmt.Fprintf(w, "case %v:\n", strings.Join(names, ", "))
+	fmt.Fprintf(w, "default:\n")
+	w.fail(generateValidationError("", s.Pointer, keyword, msg))
+	fmt.Fprintf(w, "}\n")
+}
+
+// generate "oneOf" or "anyOf" validation check of a union type
+func generateUnionValidationCheck(w *validateFuncWriter, s *jsonschema.Schema, idx *jsonschema.Index) {
+	variants := goUnionVariants(s, idx)
+	fmt.Fprintf(w, "n := 0\n")
+	for _, v := range variants {
+		fmt.Fprintf(w, "if t.%v != nil {\n", v.Name)
+		fmt.Fprintf(w, "\tn++\n")
+		if isNamedType(v, idx) {
+			fmt.Fprintf(w, "\terr := t.%v.Validate()\n", v.Name)
+			fmt.Fprintf(w, "\tif err != nil {\n")
+			w.failNested("err", `""`)
+			fmt.Fprintf(w, "\t}\n")
+		}
+		fmt.Fprintf(w, "}\n")
+	}
+
+	keyword, msg := goUnionValidationMessage(s, variants)
+	if keyword == "oneOf" {
+		fmt.Fprintf(w, "if n != 1 {\n")
+	} else {
+		fmt.Fprintf(w, "if n == 0 {\n")
+	}
+	w.fail(generateValidationError("", s.Pointer, keyword, msg))
+	fmt.Fprintf(w, "}\n")
+}
+
+// generate "type" validation check of a type union and the numeric and string checks of its value
+func generateTypeUnionValidationCheck(w *validateFuncWriter, s *jsonschema.Schema) error {
+	fmt.Fprintf(w, "switch t.Type {\n")
+	if s.Nullable() {
+		fmt.Fprintf(w, "case \"null\":\n")
+	}
+	for _, t := range goTypeUnionTypes(s) {
+		f := goTypeUnionFields[t]
+		fmt.Fprintf(w, "case %q:\n", t)
+		err := generateValueChecks(w, s, "t."+f[0], f[1], "pattern"+s.Name, func(cond, keyword, msg string) {
+			fmt.Fprintf(w, "if %v {\n", cond)
+			w.fail(generateValidationError("", s.Pointer, keyword, fmt.Sprintf("invalid %v: %v", s.JSONName, msg)))
+			fmt.Fprintf(w, "}\n")
+		})
+		if err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(w, "default:\n")
+	w.fail(generateValidationError("", s.Pointer, "type", goTypeUnionValidationMessage(s)))
+	fmt.Fprintf(w, "}\n")
+	return nil
+}
+
+// writes the body of a validate func
+type validateFuncWriter struct {
+	bytes.Buffer
+	opts Options
+
+	// package level declarations used by the validate func
+	decls bytes.Buffer
+}
+
+// writes a failed validation, returning or collecting the ValidationError literal ve
+func (w *validateFuncWriter) fail(ve string) {
+	if w.opts.AllErrors {
+		fmt.Fprintf(w, "errs = append(errs, %s)\n", ve)
+		return
+	}
+	fmt.Fprintf(w, "return %s\n", ve)
+}
+
+// writes a failed validation of a nested value, returning or collecting the error err
+func (w *validateFuncWriter) failNested(err, prefix string) {
+	if w.opts.AllErrors {
+		fmt.Fprintf(w, "errs = appendValidationErrors(errs, %s, %s)\n", err, prefix)
+		return
+	}
+	fmt.Fprintf(w, "return prefixValidationError(%s, %s)\n", err, prefix)
+}
+
+// generates a ValidationError literal
+func generateValidationError(instancePointer, schemaPointer, keyword, message string) string {
+	w := &bytes.Buffer{}
+	fmt.Fprintf(w, "&ValidationError{\n")
+	fmt.Fprintf(w, "\tInstancePointer: %q,\n", instancePointer)
+	fmt.Fprintf(w, "\tSchemaPointer: %q,\n", schemaPointer)
+	fmt.Fprintf(w, "\tKeyword: %q,\n", keyword)
+	fmt.Fprintf(w, "\tMessage: %q,\n", message)
+	fmt.Fprintf(w, "}")
+	return w.String()
+}
+
+// Generates the validation error types
+func generateGoValidationErrorTypes(opts Options) ([]byte, error) {
+	b := bytes.NewBufferString(`
+// ValidationError describes a value violating a schema keyword
+type ValidationError struct {
+	// JSON pointer to the invalid value, empty for the validated value itself
+	InstancePointer string
+
+	// JSON pointer of the violated schema
+	SchemaPointer string
+
+	// Violated keyword, e.g. required
+	Keyword string
+
+	// Human readable description of the violation
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+// ValidationErrors collects multiple validation errors of a single value
+type ValidationErrors []*ValidationError
+
+func (e ValidationErrors) Error() string {
+	msg := ""
+	for i, err := range e {
+		if i > 0 {
+			msg += "; "
+		}
+		msg += err.Error()
+	}
+	return msg
+}
+
+// prefixes the instance pointers of validation errors with the pointer of the validated value
+func prefixValidationError(err error, prefix string) error {
+	switch e := err.(type) {
+	case *ValidationError:
+		p := *e
+		p.InstancePointer = prefix + p.InstancePointer
+		return &p
+	case ValidationErrors:
+		ps := make(ValidationErrors, 0, len(e))
+		for _, ve := range e {
+			p := *ve
+			p.InstancePointer = prefix + p.InstancePointer
+			ps = append(ps, &p)
+		}
+		return ps
+	}
+	return err
+}
+	`)
+
+	if opts.AllErrors {
+		fmt.Fprintf(b, `
+// appends the validation errors of a nested value
+func appendValidationErrors(errs ValidationErrors, err error, prefix string) ValidationErrors {
+	switch e := prefixValidationError(err, prefix).(type) {
+	case *ValidationError:
+		return append(errs, e)
+	case ValidationErrors:
+		return append(errs, e...)
+	}
+	return append(errs, &ValidationError{InstancePointer: prefix, Message: err.Error()})
+}
+		`)
+	}
+
+	return format.Source(b.Bytes())
+}
+
+// Generates the generic type of fields distinguishing null from absent values
+func generateGoNullableType() ([]byte, error) {
+	b := bytes.NewBufferString(`
+// Nullable is a value which may be absent, null or valid
+type Nullable[T any] struct {
+	// Value of a valid field
+	Value T
+
+	// Valid is true if the value is present and not null
+	Valid bool
+
+	// Set is true if the value is present, including null
+	Set bool
+}
+
+// NewNullable returns a valid value
+func NewNullable[T any](v T) Nullable[T] {
+	return Nullable[T]{Value: v, Valid: true, Set: true}
+}
+
+// Null returns an explicit null value
+func Null[T any]() Nullable[T] {
+	return Nullable[T]{Set: true}
+}
+
+// IsZero reports an absent value to omit it from encoded objects
+func (n Nullable[T]) IsZero() bool {
+	return !n.Set
+}
+
+func (n Nullable[T]) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(n.Value)
+}
+
+func (n *Nullable[T]) UnmarshalJSON(b []byte) error {
+	*n = Nullable[T]{Set: true}
+	if string(b) == "null" {
+		return nil
+	}
+	err := json.Unmarshal(b, &n.Value)
+	if err != nil {
+		return err
+	}
+	n.Valid = true
+	return nil
+}
+`)
+
+	return format.Source(b.Bytes())
+}
+
+// Generates primitive type new funcs
+func generateGoPrimitiveTypesNewFuncs() ([]byte, error) {
+	b := bytes.NewBufferString(`
+func newString(s string) *string {
+	return &s
+}
+
+func newInt(i int) *int {
+	return &i
+}
+
+func newFloat(f float64) *float64 {
+	return &f
+}
+
+func newBool(b bool) *bool {
+	return &b
+}
+	`)
+
+	return format.Source(b.Bytes())
+}
+
+// creates a go friendly name from string parts
+func goNameFromStrings(parts ...string) string {
+	name := ""
+	re := regexp.MustCompile("{|}|-|_")
+	for _, p := range parts {
+		c := re.ReplaceAllString(p, "")
+		switch c {
+		case "id":
+			name += "ID"
+		case "url":
+			name += "URL"
+		case "api":
+			name += "API"
+		default:
+			name += strings.Title(c)
+		}
+	}
+	return name
+}
+
+// returns the instance pointer of a property below the validated value
+func instancePointerOf(name string) string {
+	return "/" + jsonschema.EscapeToken(name)
+}
+
+// returns true if a schema is located below a keyword whose subschemas only constrain values, e.g. not
+func isConstraintSchema(s *jsonschema.Schema) bool {
+	tokens := jsonschema.Pointer(s.Pointer).Tokens()
+	for i := 0; i < len(tokens); i++ {
+		switch tokens[i] {
+		case "definitions", "$defs", "properties", "patternProperties", "allOf", "anyOf", "oneOf", "prefixItems":
+			// skip names and indices
+			i++
+		case "not", "if", "then", "else", "propertyNames", "dependentSchemas", "dependencies",
+			"unevaluatedItems", "unevaluatedProperties":
+			return true
+		}
+	}
+	return false
+}
+
+// returns map keys sorted by alphapet
+func sortedMapKeys(m jsonschema.Schemas) []string {
+	var keys []string
+	for k, _ := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// returns map keys of named schemas sorted by schema names
+func sortedMapKeysbyName(m *jsonschema.Index) []string {
+	var schemas []*jsonschema.Schema
+	m.Walk(func(ptr string, v *jsonschema.Schema) error {
+		// skip unnamed schemas, Walk skips aliases of $id and $anchor
+		if v.Name != "" && !isConstraintSchema(v) {
+			schemas = append(schemas, v)
+		}
+		return nil
+	})
+	sort.Stable(byName(schemas))
 
 	var keys []string
 	for _, v := range schemas {
@@ -7,6 +7,7 @@ import (
 	"io/ioutil"
 	"os"
 	"os/exec"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -50,7 +51,7 @@ func TestGenerateWithDefinitions(t *testing.T) {
 		"#/definitions/categories/items":                       "",
 	}
 	for p, g := range table {
-		s := (*idx)[p]
+		s := schemaAt(idx, p)
 		gos, err := generateGoType(s, idx)
 		if err != nil {
 			t.Fatal(err)
@@ -110,7 +111,7 @@ func TestGenerateWithPrimitiveTypes(t *testing.T) {
 		"#/definitions/string":  "String *string `json:\"string,omitempty\"`",
 	}
 	for p, r := range table {
-		s := (*idx)[p]
+		s := schemaAt(idx, p)
 		ref := generateGoRef(s, idx)
 		if ref != r {
 			t.Fatalf("ref type of %v should be '%v' but is '%s'", p, r, ref)
@@ -118,6 +119,266 @@ func TestGenerateWithPrimitiveTypes(t *testing.T) {
 	}
 }
 
+func TestGenerateEnumTypes(t *testing.T) {
+	idx, err := jsonschema.Parse([]byte(fixture.TestSchemaEnumValidation))
+	if err != nil {
+		panic(err)
+	}
+
+	status := ""
+	status += "type Status string\n"
+	status += "\n"
+	status += "const (\n"
+	status += "	StatusReleased     Status = \"released\"\n"
+	status += "	StatusInProduction Status = \"in-production\"\n"
+	status += "	StatusPlanned      Status = \"planned\"\n"
+	status += ")\n"
+
+	rating := ""
+	rating += "type Rating int\n"
+	rating += "\n"
+	rating += "const (\n"
+	rating += "	Rating1 Rating = 1\n"
+	rating += "	Rating2 Rating = 2\n"
+	rating += "	Rating3 Rating = 3\n"
+	rating += "	Rating4 Rating = 4\n"
+	rating += "	Rating5 Rating = 5\n"
+	rating += ")\n"
+
+	kind := ""
+	kind += "type Kind string\n"
+	kind += "\n"
+	kind += "const (\n"
+	kind += "	KindMovie Kind = \"movie\"\n"
+	kind += ")\n"
+
+	movie := ""
+	movie += "type Movie struct {\n"
+	movie += "	ID     *string `json:\"id,omitempty\"`\n"
+	movie += "	Kind   *Kind   `json:\"kind,omitempty\"`\n"
+	movie += "	Rating *Rating `json:\"rating,omitempty\"`\n"
+	movie += "	Status *Status `json:\"status,omitempty\"`\n"
+	movie += "}\n"
+
+	table := map[string]string{
+		"#/definitions/movie":                   movie,
+		"#/definitions/movie/properties/status": status,
+		"#/definitions/movie/properties/kind":   kind,
+		"#/definitions/rating":                  rating,
+	}
+	for p, g := range table {
+		s := schemaAt(idx, p)
+		gos, err := generateGoType(s, idx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(gos) != g {
+			t.Fatalf("type of %v should be '%v' but is '%s'", p, g, gos)
+		}
+	}
+}
+
+func TestGenerateCompositionTypes(t *testing.T) {
+	idx, err := jsonschema.Parse([]byte(fixture.TestSchemaCompositionValidation))
+	if err != nil {
+		panic(err)
+	}
+
+	cat := ""
+	cat += "type Cat struct {\n"
+	cat += "	Animal\n"
+	cat += "	CatAllOf1\n"
+	cat += "	Indoor *bool `json:\"indoor,omitempty\"`\n"
+	cat += "}\n"
+
+	pet := ""
+	pet += "type Pet struct {\n"
+	pet += "	Cat *Cat\n"
+	pet += "	Dog *Dog\n"
+	pet += "}\n"
+
+	gos, err := generateGoType(schemaAt(idx, "#/definitions/cat"), idx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(gos) != cat {
+		t.Fatalf("type of #/definitions/cat should be '%v' but is '%s'", cat, gos)
+	}
+
+	gos, err = generateGoType(schemaAt(idx, "#/definitions/pet"), idx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(gos), pet) {
+		t.Fatalf("type of #/definitions/pet should start with '%v' but is '%s'", pet, gos)
+	}
+
+	// subschemas of not constrain values only
+	gts, err := generateGoTypes(idx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(gts), "type Not ") {
+		t.Fatalf("types should not contain a type for #/definitions/owner/properties/name/not but are '%s'", gts)
+	}
+}
+
+func TestGenerateCyclicUnionTypes(t *testing.T) {
+	idx, err := jsonschema.Parse([]byte(`{"definitions": {
+		"a": {"oneOf": [{"$ref": "#/definitions/b"}, {"$ref": "#/definitions/c"}]},
+		"b": {"oneOf": [{"$ref": "#/definitions/a"}, {"$ref": "#/definitions/c"}]},
+		"c": {"type": "object", "properties": {"x": {"type": "string"}}}
+	}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// unions which are variants of each other have no go type and are left to runtime validation
+	src, err := PackageSrc(idx, "main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(src), "type C struct") || strings.Contains(string(src), "type A ") {
+		t.Fatalf("types should contain C but not A, but are '%s'", src)
+	}
+}
+
+func TestGenerateAdditionalPropertiesTypes(t *testing.T) {
+	idx, err := jsonschema.Parse([]byte(fixture.TestSchemaAdditionalPropertiesValidation))
+	if err != nil {
+		panic(err)
+	}
+
+	table := map[string]string{
+		"#/definitions/labels": "type Labels map[string]string\n",
+		"#/definitions/scores": "type Scores map[string]int\n",
+		"#/definitions/movie":  "type Movie struct {\n",
+		"#/definitions/actor":  "type Actor struct {\n\tName                 *string             `json:\"name,omitempty\"`\n\tAdditionalProperties map[string]Location `json:\"-\"`\n}\n",
+	}
+	for p, typ := range table {
+		gos, err := generateGoType(schemaAt(idx, p), idx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasPrefix(string(gos), typ) {
+			t.Fatalf("type of %v should start with '%v' but is '%s'", p, typ, gos)
+		}
+	}
+
+	// additionalProperties false is enforced by validation only
+	gos, err := generateGoType(schemaAt(idx, "#/definitions/movie"), idx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(gos), "AdditionalProperties") {
+		t.Fatalf("type of #/definitions/movie should not keep additional properties but is '%s'", gos)
+	}
+}
+
+func TestGenerateExternalRefTypes(t *testing.T) {
+	idx, err := jsonschema.ParseWithLoader("schema.json", jsonschema.MapLoader{
+		"schema.json": []byte(fixture.TestSchemaExternalRef),
+		"common.json": []byte(fixture.TestSchemaExternalRefCommon),
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	table := map[string]string{
+		"#/definitions/person":             "type Person struct {\n\tAddress *Address `json:\"address,omitempty\"`\n",
+		"common.json#/definitions/address": "type Address struct {\n\tCity    *string `json:\"city,omitempty\"`\n\tCountry *string `json:\"country,omitempty\"`\n}\n",
+	}
+	for p, typ := range table {
+		gos, err := generateGoType(schemaAt(idx, p), idx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasPrefix(string(gos), typ) {
+			t.Fatalf("type of %v should start with '%v' but is '%s'", p, typ, gos)
+		}
+	}
+}
+
+func TestGenerateInferredTypes(t *testing.T) {
+	idx, err := jsonschema.Parse([]byte(fixture.TestSchemaTypeInference))
+	if err != nil {
+		panic(err)
+	}
+
+	gos, err := generateGoType(schemaAt(idx, "#/definitions/document"), idx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	typ := "type Document struct {\n" +
+		"\tAuthor *Author         `json:\"author,omitempty\"`\n" +
+		"\tExtra  interface{}     `json:\"extra,omitempty\"`\n" +
+		"\tID     *string         `json:\"id,omitempty\"`\n" +
+		"\tKind   json.RawMessage `json:\"kind,omitempty\"`\n" +
+		"\tMeta   interface{}     `json:\"meta,omitempty\"`\n" +
+		"\tTags   *Tags           `json:\"tags,omitempty\"`\n" +
+		"}\n"
+	if string(gos) != typ {
+		t.Fatalf("type of document should be '%v' but is '%s'", typ, gos)
+	}
+
+	// required alone does not infer object, as there are no properties to generate fields for
+	schemas := []string{
+		`{"definitions": {"u": {"required": ["a"]}}}`,
+		`{"definitions": {"base": {"type": "object", "properties": {"x": {"type": "string"}}}, "movie": {"allOf": [{"$ref": "#/definitions/base"}, {"required": ["x"]}]}}}`,
+		`{"definitions": {"key": {"type": "object", "properties": {"k": {"type": "string"}, "j": {"type": "string"}}, "anyOf": [{"required": ["k"]}, {"required": ["j"]}]}}}`,
+	}
+	for _, schema := range schemas {
+		idx, err := jsonschema.Parse([]byte(schema))
+		if err != nil {
+			panic(err)
+		}
+		_, err = PackageSrc(idx, "main")
+		if err != nil {
+			t.Fatalf("generating %v should succeed but failed with %v", schema, err)
+		}
+	}
+}
+
+func TestGenerateErrorPositions(t *testing.T) {
+	schema := "{\n  \"definitions\": {\n    \"movie\": {\n      \"type\": \"object\",\n      \"properties\": {\"id\": {\"type\": \"string\", \"pattern\": \"^[a-z+$\"}}\n    }\n  }\n}"
+	idx, err := jsonschema.ParseWithOptions([]byte(schema), jsonschema.ParseOptions{File: "schema.json"})
+	if err != nil {
+		panic(err)
+	}
+
+	_, err = PackageSrc(idx, "main")
+	msg := "jsonschema: schema.json:5:28: invalid pattern ^[a-z+$ of #/definitions/movie/properties/id: error parsing regexp: missing closing ]: `[a-z+$`"
+	if err == nil || err.Error() != msg {
+		t.Fatalf("generating %v should fail with '%v' but failed with '%v'", schema, msg, err)
+	}
+}
+
+func TestGenerateTupleTypes(t *testing.T) {
+	table := []struct {
+		RawSchema string
+		Pointer   string
+		Type      string
+	}{
+		{fixture.TestSchemaDraft202012, "#/$defs/point", "type Point []float64\n"},
+		{fixture.TestSchemaDraft202012, "#/$defs/measurement", "type Measurement []interface{}\n"},
+		{fixture.TestSchemaDraft7, "#/definitions/point", "type Point []float64\n"},
+		{fixture.TestSchemaDraft7, "#/definitions/names", "type Names []string\n"},
+	}
+	for _, ts := range table {
+		idx, err := jsonschema.Parse([]byte(ts.RawSchema))
+		if err != nil {
+			t.Fatal(err)
+		}
+		gos, err := generateGoType(schemaAt(idx, ts.Pointer), idx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(gos) != ts.Type {
+			t.Fatalf("type of %v should be '%v' but is '%s'", ts.Pointer, ts.Type, gos)
+		}
+	}
+}
+
 func TestGenerateGoTypeValidateFuncWithDefinitions(t *testing.T) {
 	table := []struct {
 		RawSchema string
@@ -235,6 +496,583 @@ func TestGenerateGoTypeValidateFuncWithDefinitions(t *testing.T) {
 				}
 			`,
 		},
+		{
+			fixture.TestSchemaRequiredValidation,
+			"#/definitions/movie", "/actors/1/location/name #/definitions/location required", `
+				m := Movie{ID: newString("foo"), Actors: &Actors{
+					Actor{Name: newString("John Snow"), Location: &Location{Name: newString("Winterfell")}},
+					Actor{Name: newString("Arya Stark"), Location: &Location{}},
+				}}
+				err := m.Validate()
+				if err, ok := err.(*ValidationError); ok {
+					fmt.Print(err.InstancePointer, " ", err.SchemaPointer, " ", err.Keyword)
+				}
+			`,
+		},
+		{
+			fixture.TestSchemaEnumValidation,
+			"#/definitions/movie", "", `
+				s := StatusReleased
+				r := Rating(5)
+				m := Movie{ID: newString("foo"), Status: &s, Rating: &r}
+				err := m.Validate()
+				if err != nil {
+					fmt.Print(err)
+				}
+			`,
+		},
+		{
+			fixture.TestSchemaEnumValidation,
+			"#/definitions/movie", "/status invalid status: must be one of released, in-production, planned", `
+				s := Status("cancelled")
+				m := Movie{ID: newString("foo"), Status: &s}
+				err := m.Validate()
+				if err, ok := err.(*ValidationError); ok {
+					fmt.Print(err.InstancePointer, " ", err)
+				}
+			`,
+		},
+		{
+			fixture.TestSchemaEnumValidation,
+			"#/definitions/movie", "invalid kind: must be movie", `
+				k := Kind("series")
+				m := Movie{ID: newString("foo"), Kind: &k}
+				err := m.Validate()
+				if err != nil {
+					fmt.Print(err)
+				}
+			`,
+		},
+		{
+			fixture.TestSchemaCompositionValidation,
+			"#/definitions/owner", "Dog Rex", `
+				o := Owner{}
+				err := json.Unmarshal([]byte(` + "`" + `{"pet": {"name": "Rex", "breed": "Beagle"}}` + "`" + `), &o)
+				if err != nil {
+					panic(err)
+				}
+				err = o.Validate()
+				if err != nil {
+					panic(err)
+				}
+				if o.Pet.Cat == nil && o.Pet.Dog != nil {
+					fmt.Print("Dog ", *o.Pet.Dog.Name)
+				}
+			`,
+		},
+		{
+			fixture.TestSchemaCompositionValidation,
+			"#/definitions/owner", "invalid pet: must match exactly one of Cat, Dog", `
+				o := Owner{}
+				err := json.Unmarshal([]byte(` + "`" + `{"pet": {"name": "Rex"}}` + "`" + `), &o)
+				if err != nil {
+					fmt.Print(err)
+				}
+			`,
+		},
+		{
+			fixture.TestSchemaCompositionValidation,
+			"#/definitions/cat", "/lives invalid cat: missing lives", `
+				c := Cat{Animal: Animal{Name: newString("Tom")}}
+				err := c.Validate()
+				if err, ok := err.(*ValidationError); ok {
+					fmt.Print(err.InstancePointer, " ", err)
+				}
+			`,
+		},
+		{
+			fixture.TestSchemaCompositionValidation,
+			"#/definitions/owner", `{"pet":{"name":"Tom","lives":9},"tags":["a",1]}`, `
+				o := Owner{}
+				err := json.Unmarshal([]byte(` + "`" + `{"pet": {"name": "Tom", "lives": 9}, "tags": ["a", 1]}` + "`" + `), &o)
+				if err != nil {
+					panic(err)
+				}
+				err = o.Validate()
+				if err != nil {
+					panic(err)
+				}
+				b, err := json.Marshal(o)
+				if err != nil {
+					panic(err)
+				}
+				fmt.Print(string(b))
+			`,
+		},
+		{
+			fixture.TestSchemaCompositionValidation,
+			"#/definitions/owner", "invalid tag: must match at least one of TagAnyOf0, TagAnyOf1", `
+				o := Owner{}
+				err := json.Unmarshal([]byte(` + "`" + `{"tags": [true]}` + "`" + `), &o)
+				if err != nil {
+					fmt.Print(err)
+				}
+			`,
+		},
+		{
+			fixture.TestSchemaAdditionalPropertiesValidation,
+			"#/definitions/actor", `{"home":{"name":"Winterfell"},"name":"John Snow"} Winterfell`, `
+				a := Actor{}
+				err := json.Unmarshal([]byte(` + "`" + `{"name": "John Snow", "home": {"name": "Winterfell"}}` + "`" + `), &a)
+				if err != nil {
+					panic(err)
+				}
+				err = a.Validate()
+				if err != nil {
+					panic(err)
+				}
+				b, err := json.Marshal(a)
+				if err != nil {
+					panic(err)
+				}
+				fmt.Print(string(b), " ", *a.AdditionalProperties["home"].Name)
+			`,
+		},
+		{
+			fixture.TestSchemaAdditionalPropertiesValidation,
+			"#/definitions/actor", "/home/name invalid location: missing name", `
+				a := Actor{AdditionalProperties: map[string]Location{"home": Location{}}}
+				err := a.Validate()
+				if err, ok := err.(*ValidationError); ok {
+					fmt.Print(err.InstancePointer, " ", err)
+				}
+			`,
+		},
+		{
+			fixture.TestSchemaAdditionalPropertiesValidation,
+			"#/definitions/scores", "9", `
+				s := Scores{}
+				err := json.Unmarshal([]byte(` + "`" + `{"alien": 9}` + "`" + `), &s)
+				if err != nil {
+					panic(err)
+				}
+				fmt.Print(s["alien"])
+			`,
+		},
+		{
+			fixture.TestSchemaConstraintValidation,
+			"#/definitions/movie", "<nil>", `
+				m := Movie{ID: newString("alien-1979"), Title: newString("Alien"), Year: newInt(1979), Rating: newFloat(8.5)}
+				fmt.Print(m.Validate())
+			`,
+		},
+		{
+			fixture.TestSchemaConstraintValidation,
+			"#/definitions/movie", "invalid id: must match ^[a-z0-9-]+$", `
+				m := Movie{ID: newString("Alien")}
+				fmt.Print(m.Validate())
+			`,
+		},
+		{
+			fixture.TestSchemaConstraintValidation,
+			"#/definitions/movie", "invalid title: must be at least 1 characters long", `
+				m := Movie{ID: newString("1"), Title: newString("")}
+				fmt.Print(m.Validate())
+			`,
+		},
+		{
+			fixture.TestSchemaConstraintValidation,
+			"#/definitions/movie", "<nil>", `
+				m := Movie{ID: newString("1"), Title: newString("Ünïcödé"), Rating: newFloat(0.3)}
+				fmt.Print(m.Validate())
+			`,
+		},
+		{
+			fixture.TestSchemaConstraintValidation,
+			"#/definitions/movie", "invalid year: must be >= 1900", `
+				m := Movie{ID: newString("1"), Year: newInt(1899)}
+				fmt.Print(m.Validate())
+			`,
+		},
+		{
+			fixture.TestSchemaConstraintValidation,
+			"#/definitions/movie", "invalid rating: must be a multiple of 0.1", `
+				m := Movie{ID: newString("1"), Rating: newFloat(0.35)}
+				fmt.Print(m.Validate())
+			`,
+		},
+		{
+			fixture.TestSchemaConstraintValidation,
+			"#/definitions/movie", "/runtime #/definitions/minutes multipleOf", `
+				m := Movie{ID: newString("1"), Runtime: newInt(112)}
+				err := m.Validate()
+				if err, ok := err.(*ValidationError); ok {
+					fmt.Print(err.InstancePointer, " ", err.SchemaPointer, " ", err.Keyword)
+				}
+			`,
+		},
+		{
+			fixture.TestSchemaConstraintValidation,
+			"#/definitions/movie", "invalid score: must be <= 99.5", `
+				m := Movie{ID: newString("1"), Score: newInt(100)}
+				fmt.Print(m.Validate())
+			`,
+		},
+		{
+			fixture.TestSchemaArrayValidation,
+			"#/definitions/movie", "<nil>", `
+				p := PermissionsAll
+				r := Roles{Role{Name: newString("root"), Permissions: (*string)(&p)}, Role{Name: newString("guest")}}
+				fmt.Print(r.Validate())
+			`,
+		},
+		{
+			fixture.TestSchemaArrayValidation,
+			"#/definitions/movie", "invalid roles: must have at least 1 items", `
+				r := Roles{}
+				fmt.Print(r.Validate())
+			`,
+		},
+		{
+			fixture.TestSchemaArrayValidation,
+			"#/definitions/movie", "invalid roles: must contain at least 1 matching items", `
+				r := Roles{Role{Name: newString("guest")}}
+				fmt.Print(r.Validate())
+			`,
+		},
+		{
+			fixture.TestSchemaArrayValidation,
+			"#/definitions/movie", "invalid roles: items must be unique", `
+				r := Roles{Role{Name: newString("guest")}, Role{Name: newString("guest")}}
+				fmt.Print(r.Validate())
+			`,
+		},
+		{
+			fixture.TestSchemaArrayValidation,
+			"#/definitions/movie", "<nil> invalid tags: must contain at most 1 matching items", `
+				t1 := Tags{"horror", "new"}
+				t2 := Tags{"new", "horror", "new"}
+				fmt.Print(t1.Validate(), " ", t2.Validate())
+			`,
+		},
+		{
+			fixture.TestSchemaArrayValidation,
+			"#/definitions/movie", "invalid scores: items must be unique", `
+				s := Scores{1, 2, 1}
+				fmt.Print(s.Validate())
+			`,
+		},
+		{
+			`{"definitions": {"scores": {"type": "array", "items": {"type": "integer"}, "contains": {"type": "integer", "minimum": 5}}}}`,
+			"#/definitions/scores", "<nil> invalid scores: must contain at least 1 matching items", `
+				s1 := Scores{1, 7}
+				s2 := Scores{1, 2}
+				fmt.Print(s1.Validate(), " ", s2.Validate())
+			`,
+		},
+		{
+			fixture.TestSchemaArrayValidation,
+			"#/definitions/movie", "/cast #/definitions/cast minContains", `
+				m := Movie{Cast: &Cast{Role{Name: newString("John Snow")}}}
+				err := m.Validate()
+				if err, ok := err.(*ValidationError); ok {
+					fmt.Print(err.InstancePointer, " ", err.SchemaPointer, " ", err.Keyword)
+				}
+			`,
+		},
+		{
+			fixture.TestSchemaObjectValidation,
+			"#/definitions/movie", "<nil>", `
+				m := Movie{ID: newString("1"), Title: newString("Alien")}
+				fmt.Print(m.Validate())
+			`,
+		},
+		{
+			fixture.TestSchemaObjectValidation,
+			"#/definitions/movie", "invalid movie: must have at least 1 properties", `
+				m := Movie{}
+				fmt.Print(m.Validate())
+			`,
+		},
+		{
+			fixture.TestSchemaObjectValidation,
+			"#/definitions/movie", "invalid movie: must have at most 3 properties", `
+				m := Movie{ID: newString("1"), Title: newString("Alien"), Sequel: newString("Aliens"), Prequel: newString("Prometheus")}
+				fmt.Print(m.Validate())
+			`,
+		},
+		{
+			fixture.TestSchemaObjectValidation,
+			"#/definitions/movie", "/title #/definitions/movie dependentRequired", `
+				m := Movie{Sequel: newString("Aliens")}
+				err := m.Validate()
+				if err, ok := err.(*ValidationError); ok {
+					fmt.Print(err.InstancePointer, " ", err.SchemaPointer, " ", err.Keyword)
+				}
+			`,
+		},
+		{
+			fixture.TestSchemaObjectValidation,
+			"#/definitions/movie", "/id #/definitions/movie/dependentSchemas/prequel required", `
+				m := Movie{Prequel: newString("Prometheus")}
+				err := m.Validate()
+				if err, ok := err.(*ValidationError); ok {
+					fmt.Print(err.InstancePointer, " ", err.SchemaPointer, " ", err.Keyword)
+				}
+			`,
+		},
+		{
+			fixture.TestSchemaObjectValidation,
+			"#/definitions/movie", "<nil> invalid labels: missing year required by genre", `
+				l1 := Labels{"genre": "horror", "year": "1979"}
+				l2 := Labels{"genre": "horror"}
+				fmt.Print(l1.Validate(), " ", l2.Validate())
+			`,
+		},
+		{
+			fixture.TestSchemaObjectValidation,
+			"#/definitions/movie", "invalid labels: property names must be at most 8 characters long and match ^[a-z]+$", `
+				l := Labels{"Genre": "horror"}
+				fmt.Print(l.Validate())
+			`,
+		},
+		{
+			fixture.TestSchemaObjectValidation,
+			"#/definitions/movie", "invalid series: missing season required by network", `
+				s := Series{Network: newString("HBO")}
+				fmt.Print(s.Validate())
+			`,
+		},
+		{
+			fixture.TestSchemaConditionalValidation,
+			"#/definitions/payment", "<nil>", `
+				k := KindCard
+				p := Payment{Kind: &k, CardNumber: newString("4111111111111111"), Amount: newFloat(5000)}
+				fmt.Print(p.Validate())
+			`,
+		},
+		{
+			fixture.TestSchemaConditionalValidation,
+			"#/definitions/payment", "invalid payment: missing cardNumber", `
+				k := KindCard
+				p := Payment{Kind: &k, Amount: newFloat(5)}
+				fmt.Print(p.Validate())
+			`,
+		},
+		{
+			fixture.TestSchemaConditionalValidation,
+			"#/definitions/payment", "invalid cardNumber: must match ^[0-9]{16}$", `
+				k := KindCard
+				p := Payment{Kind: &k, CardNumber: newString("4111")}
+				fmt.Print(p.Validate())
+			`,
+		},
+		{
+			fixture.TestSchemaConditionalValidation,
+			"#/definitions/payment", "<nil> invalid amount: must be <= 1000", `
+				k := KindCash
+				p1 := Payment{Kind: &k, Amount: newFloat(5)}
+				p2 := Payment{Amount: newFloat(5000)}
+				fmt.Print(p1.Validate(), " ", p2.Validate())
+			`,
+		},
+		{
+			fixture.TestSchemaConditionalValidation,
+			"#/definitions/payment", "<nil> invalid postalCode: must match ^[0-9]{5}$", `
+				a1 := Address{Country: newString("GB"), PostalCode: newString("SW1A 1AA")}
+				a2 := Address{PostalCode: newString("SW1A 1AA")}
+				fmt.Print(a1.Validate(), " ", a2.Validate())
+			`,
+		},
+		{
+			fixture.TestSchemaNullableValidation,
+			"#/definitions/profile", "<nil> invalid profile: missing nickname", `
+				var p1, p2 Profile
+				json.Unmarshal([]byte("{\"nickname\": null, \"address\": null}"), &p1)
+				json.Unmarshal([]byte("{\"address\": null}"), &p2)
+				fmt.Print(p1.Validate(), " ", p2.Validate())
+			`,
+		},
+		{
+			fixture.TestSchemaNullableValidation,
+			"#/definitions/profile", `{"nickname":null} {"age":30,"nickname":"jon"}`, `
+				b1, _ := json.Marshal(Profile{Nickname: Null[string]()})
+				b2, _ := json.Marshal(Profile{Nickname: NewNullable("jon"), Age: NewNullable(30)})
+				fmt.Printf("%s %s", b1, b2)
+			`,
+		},
+		{
+			fixture.TestSchemaNullableValidation,
+			"#/definitions/profile", "invalid nickname: must be at most 5 characters long invalid address: missing city", `
+				p1 := Profile{Nickname: NewNullable("jonathan")}
+				p2 := Profile{Nickname: Null[string](), Address: NewNullable(Address{})}
+				fmt.Print(p1.Validate(), " ", p2.Validate())
+			`,
+		},
+		{
+			`{"definitions": {"doc": {"type": "object", "maxProperties": 2,
+				"properties": {"meta": {}, "kind": {"enum": ["a", 1]}, "title": {"type": "string"}},
+				"dependentRequired": {"meta": ["title"]},
+				"if": {"required": ["kind"]},
+				"then": {"required": ["meta"]}
+			}}}`,
+			"#/definitions/doc", "<nil> invalid doc: missing title required by meta invalid doc: must have at most 2 properties invalid doc: missing meta", `
+				d1 := Doc{Meta: 1, Title: newString("x")}
+				d2 := Doc{Meta: 1}
+				d3 := Doc{Meta: 1, Title: newString("x"), Kind: json.RawMessage("1")}
+				d4 := Doc{Kind: json.RawMessage("\"a\"")}
+				fmt.Print(d1.Validate(), " ", d2.Validate(), " ", d3.Validate(), " ", d4.Validate())
+			`,
+		},
+		{
+			`{"$schema": "https://json-schema.org/draft/2020-12/schema", "$defs": {"node": {"type": "object", "required": ["name"], "properties": {
+				"name": {"type": "string"},
+				"next": {"$ref": "#/$defs/node", "type": ["object", "null"]}
+			}}}}`,
+			"#/$defs/node", "<nil> true false invalid node: missing name", `
+				var n1, n2 Node
+				json.Unmarshal([]byte("{\"name\": \"a\", \"next\": null}"), &n1)
+				json.Unmarshal([]byte("{\"name\": \"a\", \"next\": {}}"), &n2)
+				fmt.Print(n1.Validate(), " ", n1.Next.Set, " ", n1.Next.Valid, " ", n2.Validate())
+			`,
+		},
+		{
+			`{"definitions": {"movie": {"type": "object", "required": ["genre"], "properties": {
+				"genre": {"enum": ["drama", "comedy", null]}
+			}}}}`,
+			"#/definitions/movie", `<nil> invalid movie: missing genre invalid genre: must be one of drama, comedy, null {"genre":null}`, `
+				var m1, m2, m3 Movie
+				json.Unmarshal([]byte("{\"genre\": null}"), &m1)
+				json.Unmarshal([]byte("{}"), &m2)
+				json.Unmarshal([]byte("{\"genre\": \"horror\"}"), &m3)
+				b, _ := json.Marshal(Movie{Genre: Null[Genre]()})
+				fmt.Printf("%v %v %v %s", m1.Validate(), m2.Validate(), m3.Validate(), b)
+			`,
+		},
+		{
+			fixture.TestSchemaNullableValidation,
+			"#/definitions/score", `string A integer 7 "A" 7 invalid score: must be <= 100 invalid score: must be string or integer`, `
+				var s1, s2, s3 Score
+				json.Unmarshal([]byte("\"A\""), &s1)
+				json.Unmarshal([]byte("7"), &s2)
+				err := json.Unmarshal([]byte("1.5"), &s3)
+				b1, _ := json.Marshal(s1)
+				b2, _ := json.Marshal(s2)
+				s4 := Score{Type: "integer", Integer: 101}
+				fmt.Printf("%v %v %v %v %s %s %v %v", s1.Type, s1.String, s2.Type, s2.Integer, b1, b2, s4.Validate(), err)
+			`,
+		},
+		{
+			fixture.TestSchemaIDAndAnchor,
+			"#/definitions/person", "invalid zip: must match ^[0-9]{5}$ invalid address: missing city", `
+				p1 := Person{Name: newString("John"), Zip: newString("123")}
+				p2 := Person{Name: newString("John"), Address: &Address{}}
+				fmt.Print(p1.Validate(), " ", p2.Validate())
+			`,
+		},
+		{
+			fixture.TestSchemaTypeInference,
+			"#/definitions/document", "<nil> invalid document: missing meta invalid author: missing name", `
+				var d1, d2, d3 Document
+				json.Unmarshal([]byte("{\"id\": \"1\", \"meta\": {\"a\": [1]}, \"kind\": 1, \"tags\": [\"new\"]}"), &d1)
+				json.Unmarshal([]byte("{\"id\": \"1\"}"), &d2)
+				json.Unmarshal([]byte("{\"id\": \"1\", \"meta\": 1, \"author\": {}}"), &d3)
+				fmt.Print(d1.Validate(), " ", d2.Validate(), " ", d3.Validate())
+			`,
+		},
+		{
+			fixture.TestSchemaBooleanSchemas,
+			"#/definitions/movie", "<nil> /credits/0 false /pair/2 #/definitions/movie/properties/pair/additionalItems", `
+				labels := Labels{"a": 1}
+				m1 := Movie{ID: newString("1"), Meta: []interface{}{1}, Rating: "A", Tags: &Tags{1, "a"}, Pair: &Pair{"a", 1}, Labels: &labels}
+				m2 := Movie{ID: newString("1"), Credits: &Credits{"John Snow"}}
+				m3 := Movie{ID: newString("1"), Pair: &Pair{"a", 1, 2}}
+				e2, e3 := m2.Validate().(*ValidationError), m3.Validate().(*ValidationError)
+				fmt.Print(m1.Validate(), " ", e2.InstancePointer, " ", e2.Keyword, " ", e3.InstancePointer, " ", e3.SchemaPointer)
+			`,
+		},
+		{
+			fixture.TestSchemaRecursive,
+			"#/definitions/tree", "<nil> invalid name: must be at least 1 characters long /root/children/0/children/1/name", `
+				var t1, t2 Tree
+				json.Unmarshal([]byte("{\"root\": {\"name\": \"a\", \"children\": [{\"name\": \"b\", \"links\": {\"c\": {\"name\": \"c\"}}}]}}"), &t1)
+				json.Unmarshal([]byte("{\"root\": {\"name\": \"a\", \"children\": [{\"name\": \"b\", \"children\": [{\"name\": \"c\"}, {\"name\": \"\"}]}]}}"), &t2)
+				err := t2.Validate()
+				fmt.Print(t1.Validate(), " ", err, " ", err.(*ValidationError).InstancePointer)
+			`,
+		},
+		{
+			fixture.TestSchemaRecursive,
+			"#/definitions/category", `b a {"name":"c","parent":{"name":"b","parent":{"name":"a","parent":null}}}`, `
+				var c Category
+				json.Unmarshal([]byte("{\"name\": \"c\", \"parent\": {\"name\": \"b\", \"parent\": {\"name\": \"a\", \"parent\": null}}}"), &c)
+				b, _ := json.Marshal(c)
+				fmt.Printf("%v %v %s", *c.Parent.Value.Name, *c.Parent.Value.Parent.Value.Name, b)
+			`,
+		},
+		{
+			`{"definitions": {
+				"base": {"type": "object", "properties": {"id": {"type": "string"}}},
+				"movie": {"type": "object", "allOf": [{"$ref": "#/definitions/base"}], "properties": {"name": {"type": "string"}}, "required": ["id", "name"]}
+			}}`,
+			"#/definitions/movie", "invalid movie: missing id <nil>", `
+				m1 := Movie{Name: newString("Alien")}
+				m2 := Movie{Base: Base{ID: newString("1")}, Name: newString("Alien")}
+				fmt.Print(m1.Validate(), " ", m2.Validate())
+			`,
+		},
+		{
+			`{"definitions": {"counter": {"type": "object", "properties": {
+				"big": {"type": "integer", "maximum": 1e20, "exclusiveMinimum": -3e9},
+				"step": {"type": "integer", "multipleOf": 1e10}
+			}}}}`,
+			"#/definitions/counter", "<nil> invalid step: must be a multiple of 1e+10", `
+				c1 := Counter{Big: newInt(5), Step: newInt(0)}
+				c2 := Counter{Step: newInt(10)}
+				fmt.Print(c1.Validate(), " ", c2.Validate())
+			`,
+		},
+		{
+			`{"definitions": {"counter": {"type": "object", "properties": {
+				"max": {"type": "integer", "maximum": 9007199254740993},
+				"min": {"type": "integer", "exclusiveMinimum": 9007199254740992},
+				"step": {"type": "integer", "multipleOf": 9007199254740993},
+				"last": {"type": "integer", "const": 9007199254740993}
+			}}}}`,
+			"#/definitions/counter", "<nil> invalid max: must be <= 9.007199254740993e+15 invalid min: must be > 9.007199254740992e+15 invalid step: must be a multiple of 9.007199254740993e+15 invalid last: must be 9007199254740993", `
+				last := Last9007199254740993
+				c1 := Counter{Max: newInt(9007199254740993), Min: newInt(9007199254740993), Step: newInt(18014398509481986), Last: &last}
+				c2 := Counter{Max: newInt(9007199254740994)}
+				c3 := Counter{Min: newInt(9007199254740992)}
+				c4 := Counter{Step: newInt(18014398509481984)}
+				other := Last(9007199254740992)
+				c5 := Counter{Last: &other}
+				fmt.Print(c1.Validate(), " ", c2.Validate(), " ", c3.Validate(), " ", c4.Validate(), " ", c5.Validate())
+			`,
+		},
+		{
+			`{"definitions": {
+				"card": {"type": "object", "properties": {"number": {"type": "string", "pattern": "^[0-9]{16}$"}}, "required": ["number"]},
+				"paid": {"type": "object", "properties": {"number": {"type": "string", "pattern": "^[0-9]+$"}}, "required": ["number"]},
+				"payment": {"type": "object",
+					"properties": {"number": {"type": "string"}},
+					"if": {"$ref": "#/definitions/paid"},
+					"then": {"$ref": "#/definitions/card"}
+				}
+			}}`,
+			"#/definitions/payment", "<nil> invalid number: must match ^[0-9]{16}$ <nil>", `
+				p1 := Payment{Number: newString("4111111111111111")}
+				p2 := Payment{Number: newString("4111")}
+				p3 := Payment{Number: newString("x")}
+				fmt.Print(p1.Validate(), " ", p2.Validate(), " ", p3.Validate())
+			`,
+		},
+		{
+			`{"definitions": {"order": {"type": "object",
+				"properties": {"id": {"type": "string"}, "kind": {"type": "string"}},
+				"if": {"not": {"required": ["kind"]}},
+				"then": {"required": ["id"]}
+			}, "draft": {"type": "object",
+				"properties": {"id": {"type": "string"}, "note": {"type": "object", "properties": {"text": {"type": "string"}}}},
+				"if": {"properties": {"note": {"required": ["text"]}}},
+				"then": {"required": ["id"]}
+			}}}`,
+			"#/definitions/order", "<nil> <nil>", `
+				o := Order{Kind: newString("gift")}
+				d := Draft{Note: &Note{}}
+				fmt.Print(o.Validate(), " ", d.Validate())
+			`,
+		},
 	}
 	for _, ts := range table {
 		idx, err := jsonschema.Parse([]byte(ts.RawSchema))
@@ -267,13 +1105,93 @@ func main() {
 	}
 }
 
+func TestGenerateGoTypeValidateFuncAllErrors(t *testing.T) {
+	table := []struct {
+		RawSchema string
+		Error     string
+		Code      string
+	}{
+		{
+			fixture.TestSchemaRequiredValidation,
+			"invalid movie: missing id; invalid movie: missing actors", `
+				m := Movie{}
+				err := m.Validate()
+				if err != nil {
+					fmt.Print(err)
+				}
+			`,
+		},
+		{
+			fixture.TestSchemaRequiredValidation,
+			"/id /actors/0/location /actors/1/name /actors/1/location/name", `
+				m := Movie{Actors: &Actors{
+					Actor{Name: newString("John Snow")},
+					Actor{Location: &Location{}},
+				}}
+				err := m.Validate()
+				if errs, ok := err.(ValidationErrors); ok {
+					for i, e := range errs {
+						if i > 0 {
+							fmt.Print(" ")
+						}
+						fmt.Print(e.InstancePointer)
+					}
+				}
+			`,
+		},
+		{
+			fixture.TestSchemaRequiredValidation,
+			"<nil>", `
+				m := Movie{ID: newString("foo"), Actors: &Actors{}}
+				fmt.Print(m.Validate())
+			`,
+		},
+		{
+			fixture.TestSchemaConstraintValidation,
+			"invalid rating: must be a multiple of 0.1; invalid rating: must be < 10; invalid title: must be at most 10 characters long", `
+				m := Movie{ID: newString("1"), Rating: newFloat(10.05), Title: newString("Alien: Resurrection")}
+				fmt.Print(m.Validate())
+			`,
+		},
+	}
+	for _, ts := range table {
+		idx, err := jsonschema.Parse([]byte(ts.RawSchema))
+		if err != nil {
+			t.Fatal(err)
+		}
+		src, err := PackageSrcWithOptions(idx, "main", Options{AllErrors: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// inject fmt (only needed for test program runs)
+		srcs := strings.Replace(string(src), "import (", "import (\n\t\"fmt\"", 1)
+
+		w := bytes.NewBufferString(srcs)
+		fmt.Fprintf(w, `
+func main() {
+%v
+}
+`, ts.Code)
+
+		out, err := compileAndRun(w.Bytes())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if out != ts.Error {
+			t.Fatalf("%v should have produced '%v', but produced '%v'", ts, ts.Error, out)
+		}
+	}
+}
+
 func TestGenerateNewInstanceJSON(t *testing.T) {
 	idx, err := jsonschema.Parse([]byte(fixture.TestSchemaWithDefinitions))
 	if err != nil {
 		panic(err)
 	}
 
-	s := (*idx)["#/definitions/movie"]
+	s := schemaAt(idx, "#/definitions/movie")
 	inst, err := s.NewInstance(idx)
 	if err != nil {
 		t.Fatal(err)
@@ -299,6 +1217,30 @@ func TestGenerateNewInstanceJSON(t *testing.T) {
 	}
 }
 
+func TestImports(t *testing.T) {
+	table := []struct {
+		RawSchema string
+		Imports   []string
+	}{
+		{`{"definitions": {"movie": {"type": "object", "properties": {"math.score": {"type": "string"}, "fmt.x": {"type": "string"}}}}}`, []string{}},
+		{`{"definitions": {"movie": {"type": "object", "properties": {"score": {"type": "number", "multipleOf": 0.5}}}}}`, []string{"math"}},
+		{`{"definitions": {"movie": {"type": "object", "properties": {"title": {"type": "string", "maxLength": 5}}}}}`, []string{"unicode/utf8"}},
+	}
+	for _, ts := range table {
+		idx, err := jsonschema.Parse([]byte(ts.RawSchema))
+		if err != nil {
+			t.Fatal(err)
+		}
+		src, err := Src(idx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if i := Imports(src); !reflect.DeepEqual(i, ts.Imports) {
+			t.Fatalf("imports of %v should be %v but are %v", ts.RawSchema, ts.Imports, i)
+		}
+	}
+}
+
 // compiles the given code, runs it and returns the response
 func compileAndRun(code []byte) (string, error) {
 	const name = "tmp"
@@ -333,3 +1275,9 @@ func compileAndRun(code []byte) (string, error) {
 	os.RemoveAll(name)
 	return string(out), nil
 }
+
+// returns the schema of a key of an index, nil if there is none
+func schemaAt(idx *jsonschema.Index, key string) *jsonschema.Schema {
+	s, _ := idx.Get(key)
+	return s
+}
@@ -4,17 +4,100 @@ The resulting schema can be used to generate source code in any supported langua
 
 The JSON Schema implementation is based on https://tools.ietf.org/html/draft-handrews-json-schema-00.
 The validation implementation is based on http://json-schema.org/latest/json-schema-validation.html.
+The $schema of a document selects the semantics of draft-07, 2019-09 or 2020-12, see Draft.
 
 Validations
 
+Instances can be validated against any Schema of an Index at runtime, see Schema.Validate and Index.ValidateJSON.
+
+type: http://json-schema.org/latest/json-schema-validation.html#rfc.section.6.1.1
+
+properties: http://json-schema.org/latest/json-schema-validation.html#rfc.section.6.5.4
+
+items: http://json-schema.org/latest/json-schema-validation.html#rfc.section.6.4.1
+
+additionalItems: http://json-schema.org/latest/json-schema-validation.html#rfc.section.6.4.2
+
+prefixItems: https://json-schema.org/draft/2020-12/json-schema-core.html#rfc.section.10.3.1.1
+
+unevaluatedItems: https://json-schema.org/draft/2020-12/json-schema-core.html#rfc.section.11.2
+
+unevaluatedProperties: https://json-schema.org/draft/2020-12/json-schema-core.html#rfc.section.11.3
+
 required: http://json-schema.org/latest/json-schema-validation.html#rfc.section.6.5.3
 
+enum: http://json-schema.org/latest/json-schema-validation.html#rfc.section.6.1.2
+
+const: http://json-schema.org/latest/json-schema-validation.html#rfc.section.6.1.3
+
+allOf: http://json-schema.org/latest/json-schema-validation.html#rfc.section.6.7.1
+
+anyOf: http://json-schema.org/latest/json-schema-validation.html#rfc.section.6.7.2
+
+oneOf: http://json-schema.org/latest/json-schema-validation.html#rfc.section.6.7.3
+
+not: http://json-schema.org/latest/json-schema-validation.html#rfc.section.6.7.4
+
+additionalProperties: http://json-schema.org/latest/json-schema-validation.html#rfc.section.6.5.6
+
+patternProperties: http://json-schema.org/latest/json-schema-validation.html#rfc.section.6.5.5
+
+multipleOf: http://json-schema.org/latest/json-schema-validation.html#rfc.section.6.2.1
+
+maximum: http://json-schema.org/latest/json-schema-validation.html#rfc.section.6.2.2
+
+exclusiveMaximum: http://json-schema.org/latest/json-schema-validation.html#rfc.section.6.2.3
+
+minimum: http://json-schema.org/latest/json-schema-validation.html#rfc.section.6.2.4
+
+exclusiveMinimum: http://json-schema.org/latest/json-schema-validation.html#rfc.section.6.2.5
+
+maxLength: http://json-schema.org/latest/json-schema-validation.html#rfc.section.6.3.1
+
+minLength: http://json-schema.org/latest/json-schema-validation.html#rfc.section.6.3.2
+
+pattern: http://json-schema.org/latest/json-schema-validation.html#rfc.section.6.3.3
+
+maxItems: http://json-schema.org/latest/json-schema-validation.html#rfc.section.6.4.3
+
+minItems: http://json-schema.org/latest/json-schema-validation.html#rfc.section.6.4.4
+
+uniqueItems: http://json-schema.org/latest/json-schema-validation.html#rfc.section.6.4.5
+
+contains: http://json-schema.org/latest/json-schema-validation.html#rfc.section.6.4.6
+
+maxContains: https://json-schema.org/draft/2019-09/json-schema-validation.html#rfc.section.6.4.4
+
+minContains: https://json-schema.org/draft/2019-09/json-schema-validation.html#rfc.section.6.4.5
+
+maxProperties: http://json-schema.org/latest/json-schema-validation.html#rfc.section.6.5.1
+
+minProperties: http://json-schema.org/latest/json-schema-validation.html#rfc.section.6.5.2
+
+propertyNames: http://json-schema.org/latest/json-schema-validation.html#rfc.section.6.5.8
+
+dependencies: http://json-schema.org/latest/json-schema-validation.html#rfc.section.6.5.7
+
+dependentRequired: https://json-schema.org/draft/2019-09/json-schema-validation.html#rfc.section.6.5.4
+
+dependentSchemas: https://json-schema.org/draft/2019-09/json-schema-core.html#rfc.section.9.2.2.4
+
+if: http://json-schema.org/latest/json-schema-validation.html#rfc.section.6.6.1
+
+then: http://json-schema.org/latest/json-schema-validation.html#rfc.section.6.6.2
+
+else: http://json-schema.org/latest/json-schema-validation.html#rfc.section.6.6.3
+
 Generators
 
 go: https://godoc.org/github.com/tfkhsr/jsonschema/golang
 
+Linting
+
+lint: https://godoc.org/github.com/tfkhsr/jsonschema/lint
+
 
-Parse a schema into a map of JSON pointers to Schemas (Index):
+Parse a schema into an Index of JSON pointers to Schemas, read by Index.Get, Index.Range and Index.Len:
 
 	schema := []byte(`{
 	  "definitions": {
@@ -32,23 +115,144 @@ Parse a schema into a map of JSON pointers to Schemas (Index):
 		panic(err)
 	}
 
-	// idx now contains:
+	// idx now contains, likewise for $defs:
+	// "#"                       : *Schema{...}
 	// "#/definitions/user"      : *Schema{...}
 	// "#/definitions/user/id"   : *Schema{...}
 	// "#/definitions/user/name" : *Schema{...}
 
+Boolean schemas true and false are indexed like all other schemas, with Schema.Boolean set.
+
+As map order is random, the tree of an Index is traversed in document order by Index.Walk,
+Index.Roots returns the top-level definitions and Schema.Children and Index.Parent step down and up the tree:
+
+	idx.Walk(func(ptr string, s *Schema) error {
+		fmt.Println(ptr)
+		return nil
+	})
+	// #
+	// #/definitions/user
+	// #/definitions/user/properties/id
+	// #/definitions/user/properties/name
+
+Each Schema records the Position where it starts in its document. Errors of parsing and generating locate
+the offending schema by file:line:col, the file of Parse is set by ParseOptions:
+
+	_, err := ParseWithOptions(schema, ParseOptions{File: "schema.json", Strict: true})
+	// jsonschema: schema.json:8:7: unknown keyword requried at #/definitions/user/requried, did you mean required?
+
+Unknown keywords are ignored by Parse. Strict parsing reports them as KeywordErrors, suggesting the closest
+known keyword:
+
+	_, err := ParseWithOptions(schema, ParseOptions{Strict: true})
+	// jsonschema: unknown keyword requried at #/definitions/user/requried, did you mean required?
+
+Schema documents can be validated against the embedded meta-schema of their draft before parsing,
+reporting malformed keywords which do not unmarshal into a Schema:
+
+	err := ValidateSchemaDocument([]byte(`{"definitions": {"user": {"required": "id"}}}`))
+	// jsonschema: invalid instance at #/definitions/user/required: expected array but got string
+
+Schemas are written back as declared with Index.MarshalSchema or Schema.MarshalJSON, with keywords in canonical
+order. An Index is read-only and its schemas are shared by all its users, so edits are made on a copy,
+which is written back and parsed again:
+
+	user, _ := idx.Get("#/definitions/user")
+	edited := *user
+	edited.Required = []string{"id", "name"}
+	b, err := edited.MarshalJSON()
+
+Index keys are the URI fragments of JSON pointers, escaping / and ~ of names as ~1 and ~0
+and percent-encoding characters not allowed in URIs, e.g. "#/definitions/a~1b" for a definition a/b, see Pointer.
+
+References to other documents are followed by ParseWithLoader, loading documents through a Loader,
+e.g. from a file system with FSLoader, from memory with MapLoader or via HTTP with HTTPLoader:
+
+	idx, err := ParseWithLoader("schema.json", FSLoader{FS: os.DirFS("schemas")})
+	if err != nil {
+		panic(err)
+	}
+
+	// idx additionally contains the schemas of referenced documents by URI:
+	// "common.json#/definitions/address" : *Schema{...}
+
+References are resolved against the base URI established by $id as defined in
+http://json-schema.org/latest/json-schema-core.html#rfc.section.9.2. Schemas with a plain-name $id fragment
+or an $anchor as defined in https://json-schema.org/draft/2019-09/json-schema-core.html#rfc.section.8.2.3
+are indexed under that name as well, e.g. "#address" for an $anchor address.
+$recursiveRef and $dynamicRef are resolved like $ref to their initial target,
+validation follows them to the target in the dynamic scope of the instance.
+
 */
 package jsonschema
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"path"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 )
 
 // Maps JSON pointers to Schemas
-type Index map[string]*Schema
+//
+// Parsing completes an Index and its Schemas, afterwards they are only read: validation, instance creation,
+// linting and generators do not modify them, so an Index can be shared by any number of goroutines without locking.
+// Its schemas are read through Get, Range and Len.
+type Index struct {
+	schemas map[string]*Schema
+}
+
+// creates an empty index
+func newIndex() *Index {
+	return &Index{schemas: map[string]*Schema{}}
+}
+
+// Get returns the schema of a key, i.e. a JSON pointer or the URI of an $id or $anchor
+func (idx *Index) Get(key string) (*Schema, bool) {
+	if idx == nil {
+		return nil, false
+	}
+	s, ok := idx.schemas[key]
+	return s, ok
+}
+
+// Range calls fn for each key and schema of the index sorted by key, until fn returns false
+func (idx *Index) Range(fn func(key string, s *Schema) bool) {
+	if idx == nil {
+		return
+	}
+	for _, k := range idx.keys() {
+		if !fn(k, idx.schemas[k]) {
+			return
+		}
+	}
+}
+
+// Len returns the number of keys of the index, including the aliases of $id and $anchor
+func (idx *Index) Len() int {
+	if idx == nil {
+		return 0
+	}
+	return len(idx.schemas)
+}
+
+// returns the keys of an index sorted by alphabet
+func (idx *Index) keys() []string {
+	keys := make([]string, 0, len(idx.schemas))
+	for k := range idx.schemas {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Schemas maps names to the subschemas of a keyword, e.g. the properties of an object schema
+type Schemas map[string]*Schema
 
 // A schema is a part of a schema document tree
 type Schema struct {
@@ -58,80 +262,623 @@ type Schema struct {
 	// Optional Description
 	Description string `json:"description"`
 
-	// JSON pointer as defined in https://tools.ietf.org/html/rfc6901
-	Pointer string `json:"pointer"`
+	// JSON pointer as defined in https://tools.ietf.org/html/rfc6901, escaped as a Pointer
+	Pointer string `json:"-"`
 
 	// JSON pointer without #/definitions/ part
 	PointerName string
 
 	// Camel-cased name
-	Name string `json:"name"`
+	Name string `json:"-"`
 
 	// JSON friendly name
-	JSONName string `json:"jsonName"`
+	JSONName string `json:"-"`
 
-	// Type as defined in http://json-schema.org/latest/json-schema-core.html#rfc.section.4.2
+	// Type as defined in http://json-schema.org/latest/json-schema-core.html#rfc.section.4.2, computed by parsing:
+	// the only type besides null for a list of types, "" for several types besides null,
+	// or the type inferred from the keywords of a schema without type and $ref, see IsRef for a schema with $ref
 	Type string `json:"type"`
 
+	// Types as defined in http://json-schema.org/latest/json-schema-validation.html#rfc.section.6.1.1
+	// as declared in the document, a single type results in a list of one type
+	Types []string `json:"-"`
+
+	// true if Type is not given but inferred from the keywords of the schema, e.g. object for properties.
+	// An inferred type is not validated.
+	TypeInferred bool `json:"-"`
+
+	// Schema URI of the meta-schema as defined in http://json-schema.org/latest/json-schema-core.html#rfc.section.7
+	SchemaURI string `json:"$schema"`
+
+	// Draft selected by the $schema of the schema or its nearest parent, see Draft
+	Draft Draft `json:"-"`
+
 	// Definitions as defined in http://json-schema.org/latest/json-schema-validation.html#rfc.section.7.1
-	Definitions Index `json:"definitions"`
+	Definitions Schemas `json:"definitions"`
+
+	// Defs as defined in https://json-schema.org/draft/2019-09/json-schema-core.html#rfc.section.8.2.5
+	Defs Schemas `json:"$defs"`
 
 	// Properties as defined in http://json-schema.org/latest/json-schema-validation.html#rfc.section.6.18
-	Properties Index `json:"properties"`
+	Properties Schemas `json:"properties"`
+
+	// Items as defined in http://json-schema.org/latest/json-schema-validation.html#rfc.section.6.9,
+	// applies to the items after PrefixItems
+	Items *Schema `json:"-"`
+
+	// List of items as defined in http://json-schema.org/latest/json-schema-validation.html#rfc.section.6.4.1
+	// for tuples, the items after it are described by AdditionalItems
+	ItemsList []*Schema `json:"-"`
+
+	// AdditionalItems as defined in http://json-schema.org/latest/json-schema-validation.html#rfc.section.6.4.2
+	AdditionalItems *Schema `json:"additionalItems"`
+
+	// PrefixItems as defined in https://json-schema.org/draft/2020-12/json-schema-core.html#rfc.section.10.3.1.1
+	PrefixItems []*Schema `json:"prefixItems"`
 
-	// Items as defined in http://json-schema.org/latest/json-schema-validation.html#rfc.section.6.9
-	Items *Schema `json:"items"`
+	// UnevaluatedItems as defined in https://json-schema.org/draft/2020-12/json-schema-core.html#rfc.section.11.2
+	UnevaluatedItems *Schema `json:"unevaluatedItems"`
 
-	// Reference as defined in http://json-schema.org/latest/json-schema-core.html#rfc.section.8
+	// UnevaluatedProperties as defined in https://json-schema.org/draft/2020-12/json-schema-core.html#rfc.section.11.3
+	UnevaluatedProperties *Schema `json:"unevaluatedProperties"`
+
+	// Reference as defined in http://json-schema.org/latest/json-schema-core.html#rfc.section.8 as declared in the document
 	Ref string `json:"$ref"`
 
+	// Key of the schema referenced by Ref in the Index, resolved against BaseURI
+	RefKey string `json:"-"`
+
+	// ID as defined in http://json-schema.org/latest/json-schema-core.html#rfc.section.9.2
+	ID string `json:"$id"`
+
+	// Anchor as defined in https://json-schema.org/draft/2019-09/json-schema-core.html#rfc.section.8.2.3
+	Anchor string `json:"$anchor"`
+
+	// RecursiveRef as defined in https://json-schema.org/draft/2019-09/json-schema-core.html#rfc.section.8.2.4.2,
+	// its target depends on the validated instance
+	RecursiveRef string `json:"$recursiveRef"`
+
+	// RecursiveAnchor as defined in https://json-schema.org/draft/2019-09/json-schema-core.html#rfc.section.8.2.4.2.2
+	RecursiveAnchor bool `json:"$recursiveAnchor"`
+
+	// DynamicRef as defined in https://json-schema.org/draft/2020-12/json-schema-core.html#rfc.section.8.2.3.2,
+	// its target depends on the validated instance
+	DynamicRef string `json:"$dynamicRef"`
+
+	// DynamicAnchor as defined in https://json-schema.org/draft/2020-12/json-schema-core.html#rfc.section.8.2.2
+	DynamicAnchor string `json:"$dynamicAnchor"`
+
+	// Base URI of the schema established by its own or its nearest parent's $id, or the URI of its document
+	BaseURI string `json:"-"`
+
+	// Position of the start of the schema in its document
+	Position Position `json:"-"`
+
 	// Validation properties
 	Required []string `json:"required"`
+
+	// Enum as defined in http://json-schema.org/latest/json-schema-validation.html#rfc.section.6.1.2, numbers are parsed as json.Number
+	Enum []interface{} `json:"enum"`
+
+	// Const as defined in http://json-schema.org/latest/json-schema-validation.html#rfc.section.6.1.3, numbers are parsed as json.Number
+	Const interface{} `json:"const"`
+
+	// AllOf as defined in http://json-schema.org/latest/json-schema-validation.html#rfc.section.6.7.1
+	AllOf []*Schema `json:"allOf"`
+
+	// AnyOf as defined in http://json-schema.org/latest/json-schema-validation.html#rfc.section.6.7.2
+	AnyOf []*Schema `json:"anyOf"`
+
+	// OneOf as defined in http://json-schema.org/latest/json-schema-validation.html#rfc.section.6.7.3
+	OneOf []*Schema `json:"oneOf"`
+
+	// Not as defined in http://json-schema.org/latest/json-schema-validation.html#rfc.section.6.7.4
+	Not *Schema `json:"not"`
+
+	// AdditionalProperties as defined in http://json-schema.org/latest/json-schema-validation.html#rfc.section.6.5.6
+	AdditionalProperties *Schema `json:"additionalProperties"`
+
+	// PatternProperties as defined in http://json-schema.org/latest/json-schema-validation.html#rfc.section.6.5.5
+	PatternProperties Schemas `json:"patternProperties"`
+
+	// MultipleOf as defined in http://json-schema.org/latest/json-schema-validation.html#rfc.section.6.2.1
+	MultipleOf *float64 `json:"multipleOf"`
+
+	// Maximum as defined in http://json-schema.org/latest/json-schema-validation.html#rfc.section.6.2.2
+	Maximum *float64 `json:"maximum"`
+
+	// ExclusiveMaximum as defined in http://json-schema.org/latest/json-schema-validation.html#rfc.section.6.2.3
+	ExclusiveMaximum *float64 `json:"exclusiveMaximum"`
+
+	// Minimum as defined in http://json-schema.org/latest/json-schema-validation.html#rfc.section.6.2.4
+	Minimum *float64 `json:"minimum"`
+
+	// ExclusiveMinimum as defined in http://json-schema.org/latest/json-schema-validation.html#rfc.section.6.2.5
+	ExclusiveMinimum *float64 `json:"exclusiveMinimum"`
+
+	// MaxLength as defined in http://json-schema.org/latest/json-schema-validation.html#rfc.section.6.3.1
+	MaxLength *int `json:"maxLength"`
+
+	// MinLength as defined in http://json-schema.org/latest/json-schema-validation.html#rfc.section.6.3.2
+	MinLength *int `json:"minLength"`
+
+	// Pattern as defined in http://json-schema.org/latest/json-schema-validation.html#rfc.section.6.3.3
+	Pattern string `json:"pattern"`
+
+	// MaxItems as defined in http://json-schema.org/latest/json-schema-validation.html#rfc.section.6.4.3
+	MaxItems *int `json:"maxItems"`
+
+	// MinItems as defined in http://json-schema.org/latest/json-schema-validation.html#rfc.section.6.4.4
+	MinItems *int `json:"minItems"`
+
+	// UniqueItems as defined in http://json-schema.org/latest/json-schema-validation.html#rfc.section.6.4.5
+	UniqueItems bool `json:"uniqueItems"`
+
+	// Contains as defined in http://json-schema.org/latest/json-schema-validation.html#rfc.section.6.4.6
+	Contains *Schema `json:"contains"`
+
+	// MaxContains as defined in https://json-schema.org/draft/2019-09/json-schema-validation.html#rfc.section.6.4.4
+	MaxContains *int `json:"maxContains"`
+
+	// MinContains as defined in https://json-schema.org/draft/2019-09/json-schema-validation.html#rfc.section.6.4.5,
+	// defaults to 1 if contains is present
+	MinContains *int `json:"minContains"`
+
+	// MaxProperties as defined in http://json-schema.org/latest/json-schema-validation.html#rfc.section.6.5.1
+	MaxProperties *int `json:"maxProperties"`
+
+	// MinProperties as defined in http://json-schema.org/latest/json-schema-validation.html#rfc.section.6.5.2
+	MinProperties *int `json:"minProperties"`
+
+	// PropertyNames as defined in http://json-schema.org/latest/json-schema-validation.html#rfc.section.6.5.8
+	PropertyNames *Schema `json:"propertyNames"`
+
+	// DependentRequired as defined in https://json-schema.org/draft/2019-09/json-schema-validation.html#rfc.section.6.5.4,
+	// includes the property dependencies of the draft-07 dependencies keyword
+	DependentRequired map[string][]string `json:"dependentRequired"`
+
+	// DependentSchemas as defined in https://json-schema.org/draft/2019-09/json-schema-core.html#rfc.section.9.2.2.4
+	DependentSchemas Schemas `json:"dependentSchemas"`
+
+	// Schema dependencies of the dependencies keyword as defined in http://json-schema.org/latest/json-schema-validation.html#rfc.section.6.5.7
+	Dependencies Schemas `json:"-"`
+
+	// If as defined in http://json-schema.org/latest/json-schema-validation.html#rfc.section.6.6.1
+	If *Schema `json:"if"`
+
+	// Then as defined in http://json-schema.org/latest/json-schema-validation.html#rfc.section.6.6.2
+	Then *Schema `json:"then"`
+
+	// Else as defined in http://json-schema.org/latest/json-schema-validation.html#rfc.section.6.6.3
+	Else *Schema `json:"else"`
+
+	// Value of a boolean schema as defined in http://json-schema.org/latest/json-schema-core.html#rfc.section.4.3.1, nil otherwise
+	Boolean *bool `json:"-"`
+
+	// set if the parsed const is null, as Const is nil for both a missing const and a null const
+	nullConst bool
+
+	// literals of the numeric keywords as parsed, which a float64 field may round
+	numbers map[string]json.Number
+
+	// Type after parsing, a different Type was changed and is written by MarshalJSON instead of Types
+	parsedType string
+
+	// set if the schema has no keywords besides annotations and definitions
+	any bool
+
+	// keywords of subschemas whose value is null, reported by parsing
+	nulls []string
+
+	// keywords of the schema which are not known, reported by strict parsing
+	unknown []string
+
+	// raw values of keywords without a field, e.g. format and unknown keywords, written back by MarshalJSON
+	keywords map[string]json.RawMessage
+
+	// names of DependentRequired declared by the dependencies keyword
+	propertyDependencies map[string]bool
+
+	// root of the schema resource, i.e. the nearest schema with $id or the document root
+	resource *Schema
+
+	// schemas with $dynamicAnchor by their anchor, set for resource roots
+	dynamicAnchors map[string]*Schema
+
+	// index keys of the initial targets of $recursiveRef and $dynamicRef, resolved like RefKey
+	recursiveRefKey string
+	dynamicRefKey   string
 }
 
-// parse traverses the schema document tree to collect information and structure
-func (s *Schema) parse(idx *Index, pointer string) {
-	if len(s.Definitions) > 0 {
-		for name, sch := range s.Definitions {
-			sch.parse(idx, pointer+"/definitions/"+name)
+// UnmarshalJSON decodes a schema or boolean schema and records which keywords are present
+func (s *Schema) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		return errNullSchema
+	}
+	var boolean bool
+	if json.Unmarshal(b, &boolean) == nil {
+		*s = Schema{Boolean: &boolean}
+		return nil
+	}
+
+	// type is either a string or a list of strings, items either a schema or a list of schemas
+	type schema Schema
+	aux := struct {
+		*schema
+		Type  json.RawMessage `json:"type"`
+		Items json.RawMessage `json:"items"`
+		Enum  json.RawMessage `json:"enum"`
+		Const json.RawMessage `json:"const"`
+	}{schema: (*schema)(s)}
+	err := json.Unmarshal(b, &aux)
+	if err != nil {
+		return err
+	}
+	if len(aux.Type) > 0 {
+		err := s.unmarshalType(aux.Type)
+		if err != nil {
+			return err
 		}
 	}
-	if len(s.Properties) > 0 {
-		for name, sch := range s.Properties {
-			sch.parse(idx, pointer+"/properties/"+name)
+	if len(aux.Items) > 0 {
+		err := s.unmarshalItems(aux.Items)
+		if err != nil {
+			return err
 		}
 	}
-	if s.Items != nil {
-		s.Items.parse(idx, pointer+"/items")
+	// numbers of enum and const are kept as their literals to compare them exactly
+	if len(aux.Enum) > 0 {
+		err := unmarshalNumbers(aux.Enum, &s.Enum)
+		if err != nil {
+			return err
+		}
 	}
-	if pointer == "#" {
-		return
+	if len(aux.Const) > 0 {
+		err := unmarshalNumbers(aux.Const, &s.Const)
+		if err != nil {
+			return err
+		}
+	}
+
+	var keywords map[string]json.RawMessage
+	err = json.Unmarshal(b, &keywords)
+	if err != nil {
+		return err
 	}
-	if s.Ref != "" {
-		s.Type = "ref"
+	raw, ok := keywords["const"]
+	s.nullConst = ok && string(raw) == "null"
+	s.numbers = nil
+	for _, k := range numericKeywords {
+		if raw, ok := keywords[k]; ok {
+			if s.numbers == nil {
+				s.numbers = map[string]json.Number{}
+			}
+			s.numbers[k] = json.Number(raw)
+		}
 	}
+	s.nulls = nil
+	for k, raw := range keywords {
+		if string(raw) == "null" && isSchemaKeyword(k) {
+			s.nulls = append(s.nulls, k)
+		}
+	}
+	sort.Strings(s.nulls)
+	s.unknown = unknownKeywords(keywords)
+	s.keywords = nil
+	for k, raw := range keywords {
+		if !isFieldKeyword(k) {
+			if s.keywords == nil {
+				s.keywords = map[string]json.RawMessage{}
+			}
+			s.keywords[k] = raw
+		}
+	}
+	s.any = true
+	for k := range keywords {
+		if !isAnnotationKeyword(k) {
+			s.any = false
+		}
+	}
+
+	if raw, ok := keywords["dependencies"]; ok {
+		err := s.unmarshalDependencies(raw)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// unmarshalType decodes a single type or a list of types into Type and Types
+func (s *Schema) unmarshalType(raw json.RawMessage) error {
+	var typ string
+	if json.Unmarshal(raw, &typ) == nil {
+		s.Type, s.Types = typ, []string{typ}
+		return nil
+	}
+
+	err := json.Unmarshal(raw, &s.Types)
+	if err != nil {
+		return fmt.Errorf("jsonschema: type must be a string or a list of strings: %v", err)
+	}
+	s.Type = typeOfTypes(s.Types)
+	return nil
+}
+
+// returns the only type of a list of types besides null, "" for several types besides null
+func typeOfTypes(types []string) string {
+	var others []string
+	for _, t := range types {
+		if t != "null" {
+			others = append(others, t)
+		}
+	}
+	switch {
+	case len(types) == 1:
+		return types[0]
+	case len(others) == 1:
+		return others[0]
+	}
+	return ""
+}
 
-	s.Pointer = pointer
-	s.PointerName = strings.Replace(pointer, "#/definitions/", "", 1)
+// unmarshalItems decodes a schema into Items or a list of schemas into ItemsList
+func (s *Schema) unmarshalItems(raw json.RawMessage) error {
+	if string(raw) == "null" {
+		return nil
+	}
+	if bytes.HasPrefix(bytes.TrimSpace(raw), []byte("[")) {
+		return json.Unmarshal(raw, &s.ItemsList)
+	}
+	s.Items = &Schema{}
+	return json.Unmarshal(raw, s.Items)
+}
+
+// Nullable returns true if the schema allows null besides other types
+func (s *Schema) Nullable() bool {
+	if len(s.Types) < 2 {
+		return false
+	}
+	for _, t := range s.Types {
+		if t == "null" {
+			return true
+		}
+	}
+	return false
+}
+
+// unmarshalDependencies splits the draft-07 dependencies keyword into DependentRequired and Dependencies
+func (s *Schema) unmarshalDependencies(raw json.RawMessage) error {
+	var deps map[string]json.RawMessage
+	err := json.Unmarshal(raw, &deps)
+	if err != nil {
+		return err
+	}
+	for name, dep := range deps {
+		if string(dep) == "null" {
+			// kept as nil to be reported by parsing
+			if s.Dependencies == nil {
+				s.Dependencies = Schemas{}
+			}
+			s.Dependencies[name] = nil
+			continue
+		}
+		var required []string
+		if json.Unmarshal(dep, &required) == nil {
+			if s.DependentRequired == nil {
+				s.DependentRequired = map[string][]string{}
+			}
+			s.DependentRequired[name] = required
+			if s.propertyDependencies == nil {
+				s.propertyDependencies = map[string]bool{}
+			}
+			s.propertyDependencies[name] = true
+			continue
+		}
+
+		sch := &Schema{}
+		err := json.Unmarshal(dep, sch)
+		if err != nil {
+			return err
+		}
+		if s.Dependencies == nil {
+			s.Dependencies = Schemas{}
+		}
+		s.Dependencies[name] = sch
+	}
+	return nil
+}
+
+// IsAny returns true if the schema allows any value, i.e. true or a schema without keywords besides annotations like {}
+func (s *Schema) IsAny() bool {
+	if s.Boolean != nil {
+		return *s.Boolean
+	}
+	return s.any
+}
+
+// IsRef returns true if the schema has a $ref, which is resolved to the schema it references by Index.Resolve
+func (s *Schema) IsRef() bool {
+	return s.Ref != ""
+}
+
+// returns the type implied by the keywords of a schema without type, "" if there is none
+func (s *Schema) inferType() string {
+	prefix, rest := s.TupleItems()
+	switch {
+	case len(s.Properties) > 0 || len(s.PatternProperties) > 0 || s.AdditionalProperties != nil:
+		return "object"
+	case rest != nil || len(prefix) > 0:
+		return "array"
+	}
+	return ""
+}
+
+// HasConst returns true if the schema contains the const keyword, i.e. Const is not nil or the parsed const is null
+func (s *Schema) HasConst() bool {
+	return s.Const != nil || s.nullConst
+}
+
+// numericKeywords have a float64 field and a literal returned by Number
+var numericKeywords = []string{"multipleOf", "maximum", "exclusiveMaximum", "minimum", "exclusiveMinimum"}
+
+// Number returns the value of the numeric keyword multipleOf, maximum, exclusiveMaximum, minimum or exclusiveMinimum
+// as written in the document, which keeps integers beyond the precision of its float64 field.
+// A field changed after parsing is formatted instead, false is returned if the field is nil.
+func (s *Schema) Number(keyword string) (json.Number, bool) {
+	var f *float64
+	switch keyword {
+	case "multipleOf":
+		f = s.MultipleOf
+	case "maximum":
+		f = s.Maximum
+	case "exclusiveMaximum":
+		f = s.ExclusiveMaximum
+	case "minimum":
+		f = s.Minimum
+	case "exclusiveMinimum":
+		f = s.ExclusiveMinimum
+	}
+	if f == nil {
+		return "", false
+	}
+	if n, ok := s.numbers[keyword]; ok {
+		if v, err := n.Float64(); err == nil && v == *f {
+			return n, true
+		}
+	}
+	return json.Number(strconv.FormatFloat(*f, 'g', -1, 64)), true
+}
+
+// unmarshalNumbers decodes b into v with numbers as json.Number
+func unmarshalNumbers(b []byte, v interface{}) error {
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	return d.Decode(v)
+}
+
+// parse traverses the schema document tree to collect information and structure
+func (s *Schema) parse(idx *Index, pointer Pointer, base string, draft Draft) {
+	if s.ID != "" {
+		if u, _, err := resolveURI(base, s.ID); err == nil {
+			base = u
+		}
+	}
+	s.BaseURI = base
+	if s.SchemaURI != "" {
+		draft = draftOf(s.SchemaURI)
+	}
+	s.Draft = draft
+
+	// subschemas of keywords the draft does not define are kept but not indexed
+	for _, c := range s.children() {
+		ptr := pointer
+		for _, t := range c.tokens {
+			ptr = ptr.Append(t)
+		}
+		c.schema.parse(idx, ptr, base, draft)
+	}
+	if s.Type == "" && len(s.Types) == 0 && !s.IsRef() {
+		s.Type = s.inferType()
+		s.TypeInferred = s.Type != ""
+	}
+	s.parsedType = s.Type
+
+	s.Pointer = string(pointer)
+	idx.schemas[s.Pointer] = s
+
+	// the document root has no name, the root of a referenced document is named after its file
+	if pointer.Parent() == pointer {
+		if uri := pointer.URI(); uri != "" {
+			name := path.Base(uri)
+			s.Name = nameFromStrings(strings.TrimSuffix(name, path.Ext(name)))
+			s.JSONName = name
+		}
+		return
+	}
+	s.PointerName = strings.TrimPrefix(s.Pointer[len(pointer.URI()):], "#/definitions/")
+	s.PointerName = strings.TrimPrefix(s.PointerName, "#/$defs/")
 	s.Name = nameFromPointer(pointer)
 	s.JSONName = jsonNameFromPointer(pointer)
-
-	(*idx)[pointer] = s
 }
 
 // Creates a new instance conforming to the schema
+//
+// Recursive references are followed once, see NewInstanceWithDepth.
 func (s *Schema) NewInstance(idx *Index) (interface{}, error) {
-	switch s.Type {
-	case "ref":
-		sch, err := resolveRefToSchema(s, idx)
+	return s.NewInstanceWithDepth(idx, 1)
+}
+
+// NewInstanceWithDepth creates a new instance conforming to the schema, following a reference to a schema
+// at most depth times within itself. Optional properties and items of a schema nested deeper are omitted.
+func (s *Schema) NewInstanceWithDepth(idx *Index, depth int) (interface{}, error) {
+	g := &instanceGenerator{idx: idx, depth: depth, refs: map[*Schema]int{}}
+	inst, err := g.newInstance(s)
+	if err == errInstanceDepth {
+		return nil, s.Errorf("cannot create instance of %v within depth %v, required values are recursive", s.Pointer, depth)
+	}
+	return inst, err
+}
+
+// returned for schemas decoded from null, which is neither an object nor a boolean
+var errNullSchema = errors.New("jsonschema: schema must be an object or a boolean, not null")
+
+// returned for values of schemas nested deeper than the depth of an instanceGenerator
+var errInstanceDepth = errors.New("jsonschema: instance depth exceeded")
+
+// creates instances, counting the references followed on the current path
+type instanceGenerator struct {
+	idx   *Index
+	depth int
+	refs  map[*Schema]int
+}
+
+// creates a new instance conforming to s
+func (g *instanceGenerator) newInstance(s *Schema) (interface{}, error) {
+	if s.HasConst() {
+		return s.Const, nil
+	}
+	if len(s.Enum) > 0 {
+		return s.Enum[0], nil
+	}
+	if s.IsRef() {
+		sch, err := g.idx.Resolve(s)
 		if err != nil {
 			return nil, err
 		}
-		return sch.NewInstance(idx)
+		if g.refs[sch] >= g.depth {
+			return nil, errInstanceDepth
+		}
+		g.refs[sch]++
+		defer func() { g.refs[sch]-- }()
+		return g.newInstance(sch)
+	}
+	if len(s.AllOf) > 0 && (s.Type == "" || s.Type == "object") {
+		return g.newAllOfInstance(s)
+	}
+	if s.Type == "" && len(s.OneOf) > 0 {
+		return g.newInstance(s.OneOf[0])
+	}
+	if s.Type == "" && len(s.AnyOf) > 0 {
+		return g.newInstance(s.AnyOf[0])
+	}
+	typ := s.Type
+	if typ == "" && len(s.Types) > 0 {
+		typ = s.Types[0]
+	}
+	switch typ {
 	case "object":
 		m := make(map[string]interface{})
 		for name, sch := range s.Properties {
-			d, err := sch.NewInstance(idx)
+			// properties of a false schema are not allowed
+			if r, err := g.idx.Resolve(sch); err == nil && r.Boolean != nil && !*r.Boolean {
+				continue
+			}
+			d, err := g.newInstance(sch)
+			if err == errInstanceDepth && !s.requires(name) {
+				continue
+			}
 			if err != nil {
 				return nil, err
 			}
@@ -140,11 +887,24 @@ func (s *Schema) NewInstance(idx *Index) (interface{}, error) {
 		return m, nil
 	case "array":
 		a := make([]interface{}, 0)
-		d, err := s.Items.NewInstance(idx)
-		if err != nil {
-			return nil, err
+		prefix, rest := s.TupleItems()
+		for _, sch := range prefix {
+			d, err := g.newInstance(sch)
+			if err != nil {
+				return nil, err
+			}
+			a = append(a, d)
+		}
+		if rest != nil && rest.Boolean == nil {
+			d, err := g.newInstance(rest)
+			if err == errInstanceDepth {
+				return a, nil
+			}
+			if err != nil {
+				return nil, err
+			}
+			a = append(a, d)
 		}
-		a = append(a, d)
 		return a, nil
 	case "string":
 		return "string", nil
@@ -160,42 +920,131 @@ func (s *Schema) NewInstance(idx *Index) (interface{}, error) {
 	return nil, nil
 }
 
+// creates a new object instance merging the properties of all allOf schemas
+func (g *instanceGenerator) newAllOfInstance(s *Schema) (interface{}, error) {
+	schemas := []*Schema{{Type: "object", Properties: s.Properties, Required: s.Required}}
+	schemas = append(schemas, s.AllOf...)
+
+	m := make(map[string]interface{})
+	for _, sch := range schemas {
+		d, err := g.newInstance(sch)
+		if err != nil {
+			return nil, err
+		}
+		if o, ok := d.(map[string]interface{}); ok {
+			for k, v := range o {
+				m[k] = v
+			}
+		}
+	}
+	return m, nil
+}
+
+// returns true if the property name is required by the schema
+func (s *Schema) requires(name string) bool {
+	for _, r := range s.Required {
+		if r == name {
+			return true
+		}
+	}
+	return false
+}
+
 // Parse converts a raw JSON schema document to an Index of Schemas
+//
+// The Type of schemas without type is inferred from their keywords, object for properties and array for items,
+// see Schema.TypeInferred.
+//
+// Schemas with an $id or $anchor are additionally indexed by the URI they establish,
+// e.g. #address for an $anchor address. References to other documents are kept, see ParseWithLoader.
 func Parse(b []byte) (*Index, error) {
-	var s Schema
-	err := json.Unmarshal(b, &s)
-	if err != nil {
-		return nil, fmt.Errorf("jsonschema: %v", err)
-	}
+	return ParseWithOptions(b, ParseOptions{})
+}
 
-	idx := &Index{}
-	s.parse(idx, "#")
+// ParseOptions control the parsing of schema documents
+type ParseOptions struct {
+	// Report keywords which are not defined by any draft as KeywordErrors, e.g. a misspelled requried
+	Strict bool
 
-	return idx, nil
+	// Allow vendor extension keywords prefixed with x- in strict mode
+	AllowExtensions bool
+
+	// Validate documents against the meta-schema of their draft before parsing, see ValidateSchemaDocument
+	ValidateDocument bool
+
+	// File of the document parsed by ParseWithOptions, e.g. schema.json, used in positions of schemas and errors
+	File string
 }
 
-// returns a schema or referenced schema
-func resolveRefToSchema(s *Schema, idx *Index) (*Schema, error) {
-	if s.Type != "ref" {
-		return s, nil
+// ParseWithOptions converts a raw JSON schema document to an Index of Schemas using the given options
+func ParseWithOptions(b []byte, opts ParseOptions) (*Index, error) {
+	if opts.ValidateDocument {
+		err := ValidateSchemaDocument(b)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	l := newDocumentLoader("", nil)
+	err := l.add("", opts.File, b)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Strict {
+		err = checkKeywords(l.idx, opts)
+		if err != nil {
+			return nil, err
+		}
 	}
-	ref := (*idx)[s.Ref]
-	if ref == nil {
-		return nil, fmt.Errorf("jsonschema: %v does not exist in index", s.Ref)
+	return l.idx, nil
+}
+
+// Resolve returns a schema or the schema at the end of its chain of $ref,
+// failing with an error located at the schema with a cyclic or missing $ref
+func (idx *Index) Resolve(s *Schema) (*Schema, error) {
+	return idx.resolveWhile(s, func(*Schema) bool { return true })
+}
+
+// follows the $ref of schemas as long as follow returns true for them
+func (idx *Index) resolveWhile(s *Schema, follow func(*Schema) bool) (*Schema, error) {
+	seen := map[*Schema]bool{}
+	for s.IsRef() && follow(s) {
+		if seen[s] {
+			return nil, s.Errorf("cyclic $ref at %v", s.Pointer)
+		}
+		seen[s] = true
+
+		ref, ok := idx.Get(s.RefKey)
+		if !ok {
+			return nil, s.Errorf("$ref %v of %v does not exist", s.Ref, s.Pointer)
+		}
+		s = ref
 	}
-	return ref, nil
+	return s, nil
 }
 
 // creates a go friendly name from a JSON pointer
-func nameFromPointer(pointer string) string {
-	p := strings.Split(pointer, "/")
-	return nameFromStrings(p[len(p)-1])
+func nameFromPointer(pointer Pointer) string {
+	p := pointer.Tokens()
+	n := len(p)
+	switch {
+	case isSubschemaListAt(p, n-2):
+		// subschemas of allOf, anyOf, oneOf, prefixItems and lists of items are named after their parent
+		return nameFromStrings(parentNameAt(p, n-3), p[n-2], p[n-1])
+	case isKeywordAt(p, n-2) && p[n-2] == "patternProperties":
+		return nameFromStrings(parentNameAt(p, n-3), "pattern", p[n-1])
+	case isKeywordAt(p, n-2) && isDependencyKeyword(p[n-2]):
+		return nameFromStrings(parentNameAt(p, n-3), "dependent", p[n-1])
+	case isKeywordAt(p, n-1) && isSingleSubschemaKeyword(p[n-1]):
+		return nameFromStrings(parentNameAt(p, n-2), p[n-1])
+	}
+	return nameFromStrings(p[n-1])
 }
 
 // creates a go friendly name from string parts
 func nameFromStrings(parts ...string) string {
 	name := ""
-	re := regexp.MustCompile("{|}|-|_")
+	re := regexp.MustCompile(`[^\pL\pN]`)
 	for _, p := range parts {
 		c := re.ReplaceAllString(p, "")
 		switch c {
@@ -213,7 +1062,70 @@ func nameFromStrings(parts ...string) string {
 }
 
 // returns a json friendly name from a pointer
-func jsonNameFromPointer(pointer string) string {
-	p := strings.Split(pointer, "/")
-	return p[len(p)-1]
+func jsonNameFromPointer(pointer Pointer) string {
+	p := pointer.Tokens()
+	n := len(p)
+	switch {
+	case isSubschemaListAt(p, n-2) || isKeywordAt(p, n-2) && (isDependencyKeyword(p[n-2]) || p[n-2] == "patternProperties"):
+		return parentNameAt(p, n-3)
+	case isKeywordAt(p, n-1) && isSingleSubschemaKeyword(p[n-1]):
+		return parentNameAt(p, n-2)
+	}
+	return p[n-1]
+}
+
+// returns true if the pointer token at i is a keyword and not the name of a property or definition
+func isKeywordAt(p []string, i int) bool {
+	if i < 0 {
+		return false
+	}
+	if i == 0 {
+		return true
+	}
+	switch p[i-1] {
+	case "properties", "definitions", "$defs", "patternProperties", "dependentSchemas", "dependencies":
+		return !isKeywordAt(p, i-1)
+	}
+	return true
+}
+
+// returns the name of the schema at pointer token i, "" for the document root
+func parentNameAt(p []string, i int) string {
+	if i < 0 {
+		return ""
+	}
+	return p[i]
+}
+
+// returns true for keywords holding a list of subschemas
+func isCompositionKeyword(keyword string) bool {
+	return keyword == "allOf" || keyword == "anyOf" || keyword == "oneOf"
+}
+
+// returns true if the pointer token at i is a keyword holding a list of subschemas followed by an index
+func isSubschemaListAt(p []string, i int) bool {
+	if !isKeywordAt(p, i) || i+1 >= len(p) {
+		return false
+	}
+	switch p[i] {
+	case "items", "prefixItems":
+		_, err := strconv.Atoi(p[i+1])
+		return err == nil
+	}
+	return isCompositionKeyword(p[i])
+}
+
+// returns true for keywords holding a single subschema named after its parent
+func isSingleSubschemaKeyword(keyword string) bool {
+	switch keyword {
+	case "additionalProperties", "contains", "propertyNames", "if", "then", "else",
+		"additionalItems", "unevaluatedItems", "unevaluatedProperties":
+		return true
+	}
+	return false
+}
+
+// returns true for keywords holding subschemas by property name applied to the whole object
+func isDependencyKeyword(keyword string) bool {
+	return keyword == "dependentSchemas" || keyword == "dependencies"
 }
@@ -25,12 +25,12 @@ func TestWithDefinitions(t *testing.T) {
 		"#/definitions/movie/properties/actor":                 "object",
 		"#/definitions/movie/properties/actor/properties/id":   "string",
 		"#/definitions/movie/properties/actor/properties/name": "string",
-		"#/definitions/movie/properties/categories":            "ref",
+		"#/definitions/movie/properties/categories":            "",
 		"#/definitions/categories":                             "array",
 		"#/definitions/categories/items":                       "string",
 	}
 	for p, tp := range table {
-		s, ok := (*idx)[p]
+		s, ok := idx.Get(p)
 		if !ok {
 			t.Fatalf("index does not contain pointer %v", p)
 		}
@@ -57,7 +57,7 @@ func TestSchemaWithArrayOfObjects(t *testing.T) {
 		"#/definitions/movies/items/properties/year": "integer",
 	}
 	for p, tp := range table {
-		s, ok := (*idx)[p]
+		s, ok := idx.Get(p)
 		if !ok {
 			t.Fatalf("index does not contain pointer %v", p)
 		}
@@ -83,10 +83,10 @@ func TestDirectSchema(t *testing.T) {
 		"#/properties/actor":                 "object",
 		"#/properties/actor/properties/id":   "string",
 		"#/properties/actor/properties/name": "string",
-		"#/properties/categories":            "ref",
+		"#/properties/categories":            "",
 	}
 	for p, tp := range table {
-		s, ok := (*idx)[p]
+		s, ok := idx.Get(p)
 		if !ok {
 			t.Fatalf("index does not contain pointer %v", p)
 		}
@@ -114,7 +114,7 @@ func TestWithNestedDefinitions(t *testing.T) {
 		"#/definitions/movie/definitions/actor/properties/name": "string",
 	}
 	for p, tp := range table {
-		s, ok := (*idx)[p]
+		s, ok := idx.Get(p)
 		if !ok {
 			t.Fatalf("index does not contain pointer %v", p)
 		}
@@ -127,13 +127,421 @@ func TestWithNestedDefinitions(t *testing.T) {
 	}
 }
 
+func TestWithComposition(t *testing.T) {
+	idx, err := Parse([]byte(fixture.TestSchemaCompositionValidation))
+	if err != nil {
+		panic(err)
+	}
+
+	table := map[string]string{
+		"#/definitions/cat":                          "Cat",
+		"#/definitions/cat/allOf/0":                  "CatAllOf0",
+		"#/definitions/cat/allOf/1":                  "CatAllOf1",
+		"#/definitions/cat/allOf/1/properties/lives": "Lives",
+		"#/definitions/dog/allOf/0":                  "DogAllOf0",
+		"#/definitions/pet/oneOf/0":                  "PetOneOf0",
+		"#/definitions/pet/oneOf/1":                  "PetOneOf1",
+		"#/definitions/tag/anyOf/0":                  "TagAnyOf0",
+		"#/definitions/tag/anyOf/1":                  "TagAnyOf1",
+		"#/definitions/owner/properties/name/not":    "Not",
+		"#/definitions/owner/properties/tags/items":  "Items",
+		"#/definitions/owner/properties/pet":         "Pet",
+		"#/definitions/owner/properties/name":        "Name",
+		"#/definitions/animal/properties/name":       "Name",
+		"#/definitions/animal":                       "Animal",
+		"#/definitions/cat/properties/indoor":        "Indoor",
+		"#/definitions/dog/properties/breed":         "Breed",
+		"#/definitions/owner":                        "Owner",
+		"#/definitions/pet":                          "Pet",
+		"#/definitions/tag":                          "Tag",
+		"#/definitions/owner/properties/tags":        "Tags",
+		"#/definitions/dog":                          "Dog",
+	}
+	for p, name := range table {
+		s, ok := idx.Get(p)
+		if !ok {
+			t.Fatalf("index does not contain pointer %v", p)
+		}
+		if s.Name != name {
+			t.Fatalf("name of schema with pointer %v is not %v but %v", p, name, s.Name)
+		}
+		if s.Pointer != p {
+			t.Fatalf("pointer of schema should be %v but is %v", p, s.Pointer)
+		}
+	}
+	if idx.Len() != len(table)+1 {
+		t.Fatalf("index should contain %v schemas but contains %v", len(table)+1, idx.Len())
+	}
+}
+
+func TestWithAdditionalProperties(t *testing.T) {
+	idx, err := Parse([]byte(fixture.TestSchemaAdditionalPropertiesValidation))
+	if err != nil {
+		panic(err)
+	}
+
+	table := map[string]string{
+		"#/definitions/actor/additionalProperties":              "ActorAdditionalProperties",
+		"#/definitions/labels/additionalProperties":             "LabelsAdditionalProperties",
+		"#/definitions/movie/additionalProperties":              "MovieAdditionalProperties",
+		"#/definitions/scores/additionalProperties":             "ScoresAdditionalProperties",
+		"#/definitions/scores/patternProperties/%5E%5Ba-z%5D+$": "ScoresPatternAz",
+	}
+	for p, name := range table {
+		s, ok := idx.Get(p)
+		if !ok {
+			t.Fatalf("index does not contain pointer %v", p)
+		}
+		if s.Name != name {
+			t.Fatalf("name of schema with pointer %v is not %v but %v", p, name, s.Name)
+		}
+	}
+
+	ap := idx.schemas["#/definitions/movie"].AdditionalProperties
+	if ap.Boolean == nil || *ap.Boolean {
+		t.Fatalf("additionalProperties of #/definitions/movie should be false but is %v", ap.Boolean)
+	}
+}
+
+func TestWithContains(t *testing.T) {
+	idx, err := Parse([]byte(fixture.TestSchemaArrayValidation))
+	if err != nil {
+		panic(err)
+	}
+
+	s, ok := idx.Get("#/definitions/tags/contains")
+	if !ok {
+		t.Fatalf("index does not contain pointer #/definitions/tags/contains")
+	}
+	if s.Name != "TagsContains" || s.JSONName != "tags" {
+		t.Fatalf("name of #/definitions/tags/contains should be TagsContains (tags) but is %v (%v)", s.Name, s.JSONName)
+	}
+
+	tags := idx.schemas["#/definitions/tags"]
+	if tags.Contains != s || *tags.MinContains != 0 || *tags.MaxContains != 1 {
+		t.Fatalf("contains of #/definitions/tags is not parsed: %v", tags)
+	}
+}
+
+func TestWithDependencies(t *testing.T) {
+	idx, err := Parse([]byte(fixture.TestSchemaObjectValidation))
+	if err != nil {
+		panic(err)
+	}
+
+	table := map[string]string{
+		"#/definitions/movie/dependentSchemas/prequel":                "MovieDependentPrequel",
+		"#/definitions/series/dependencies/network":                   "SeriesDependentNetwork",
+		"#/definitions/series/dependencies/network/properties/season": "Season",
+		"#/definitions/labels/propertyNames":                          "LabelsPropertyNames",
+	}
+	for p, name := range table {
+		s, ok := idx.Get(p)
+		if !ok {
+			t.Fatalf("index does not contain pointer %v", p)
+		}
+		if s.Name != name {
+			t.Fatalf("name of schema with pointer %v is not %v but %v", p, name, s.Name)
+		}
+	}
+
+	series := idx.schemas["#/definitions/series"]
+	if len(series.DependentRequired["episode"]) != 1 || series.DependentRequired["episode"][0] != "season" {
+		t.Fatalf("property dependencies of #/definitions/series should be merged into DependentRequired but are %v", series.DependentRequired)
+	}
+	if _, ok := series.Dependencies["episode"]; ok {
+		t.Fatalf("property dependency episode of #/definitions/series should not be a schema dependency")
+	}
+}
+
+func TestWithConditional(t *testing.T) {
+	idx, err := Parse([]byte(fixture.TestSchemaConditionalValidation))
+	if err != nil {
+		panic(err)
+	}
+
+	table := map[string]string{
+		"#/definitions/payment/if":                         "PaymentIf",
+		"#/definitions/payment/if/properties/kind":         "Kind",
+		"#/definitions/payment/then":                       "PaymentThen",
+		"#/definitions/payment/then/properties/cardNumber": "CardNumber",
+		"#/definitions/payment/else":                       "PaymentElse",
+		"#/definitions/address/then":                       "AddressThen",
+	}
+	for p, name := range table {
+		s, ok := idx.Get(p)
+		if !ok {
+			t.Fatalf("index does not contain pointer %v", p)
+		}
+		if s.Name != name {
+			t.Fatalf("name of schema with pointer %v is not %v but %v", p, name, s.Name)
+		}
+	}
+	if _, ok := idx.Get("#/definitions/address/else"); ok {
+		t.Fatalf("index should not contain missing else of #/definitions/address")
+	}
+}
+
+func TestWithTypes(t *testing.T) {
+	idx, err := Parse([]byte(fixture.TestSchemaNullableValidation))
+	if err != nil {
+		panic(err)
+	}
+
+	table := []struct {
+		Pointer  string
+		Type     string
+		Types    string
+		Nullable bool
+	}{
+		{"#/definitions/profile", "object", "[object]", false},
+		{"#/definitions/profile/properties/nickname", "string", "[string null]", true},
+		{"#/definitions/address", "object", "[object null]", true},
+		{"#/definitions/score", "", "[string integer]", false},
+		{"#/definitions/profile/properties/score", "", "[]", false},
+	}
+	for _, ts := range table {
+		s := idx.schemas[ts.Pointer]
+		if s.Type != ts.Type {
+			t.Fatalf("type of %v is not %v but %v", ts.Pointer, ts.Type, s.Type)
+		}
+		if fmt.Sprint(s.Types) != ts.Types {
+			t.Fatalf("types of %v are not %v but %v", ts.Pointer, ts.Types, s.Types)
+		}
+		if s.Nullable() != ts.Nullable {
+			t.Fatalf("nullable of %v is not %v", ts.Pointer, ts.Nullable)
+		}
+	}
+
+	_, err = Parse([]byte(`{"type": 1}`))
+	if err == nil {
+		t.Fatal("type of a number should not parse")
+	}
+}
+
+func TestWithIDAndAnchor(t *testing.T) {
+	idx, err := Parse([]byte(fixture.TestSchemaIDAndAnchor))
+	if err != nil {
+		panic(err)
+	}
+
+	table := []struct {
+		Pointer string
+		Ref     string
+		Target  string
+	}{
+		{"#/definitions/person/properties/name", "#name", "#/definitions/name"},
+		{"#/definitions/person/properties/address", "#/definitions/address", "#/definitions/address"},
+		{"#/definitions/person/properties/city", "#/definitions/address/properties/city", "#/definitions/address/properties/city"},
+		{"#/definitions/person/properties/zip", "http://example.com/schemas/address.json#zip", "#/definitions/address/properties/zip"},
+		{"#/definitions/person/properties/age", "#age", "#/definitions/age"},
+	}
+	for _, ts := range table {
+		s := idx.schemas[ts.Pointer]
+		if s.RefKey != ts.Ref {
+			t.Fatalf("ref of %v is not %v but %v", ts.Pointer, ts.Ref, s.RefKey)
+		}
+		target, ok := idx.Get(s.RefKey)
+		if !ok {
+			t.Fatalf("index does not contain ref %v of %v", s.RefKey, ts.Pointer)
+		}
+		if target.Pointer != ts.Target {
+			t.Fatalf("ref %v of %v should resolve to %v but resolves to %v", s.RefKey, ts.Pointer, ts.Target, target.Pointer)
+		}
+	}
+
+	ids := map[string]string{
+		"http://example.com/schemas/root.json":    "#",
+		"http://example.com/schemas/address.json": "#/definitions/address",
+	}
+	for id, p := range ids {
+		if s, ok := idx.Get(id); !ok || s.Pointer != p {
+			t.Fatalf("$id %v should be an alias of %v", id, p)
+		}
+	}
+
+	bases := map[string]string{
+		"#":                                     "http://example.com/schemas/root.json",
+		"#/definitions/person":                  "http://example.com/schemas/root.json",
+		"#/definitions/address":                 "http://example.com/schemas/address.json",
+		"#/definitions/address/properties/city": "http://example.com/schemas/address.json",
+	}
+	for p, base := range bases {
+		if s := idx.schemas[p]; s.BaseURI != base {
+			t.Fatalf("base uri of %v is not %v but %v", p, base, s.BaseURI)
+		}
+	}
+}
+
+func TestWithDrafts(t *testing.T) {
+	idx, err := Parse([]byte(fixture.TestSchemaDraft202012))
+	if err != nil {
+		panic(err)
+	}
+
+	table := []struct {
+		Pointer     string
+		Name        string
+		PointerName string
+		Draft       Draft
+	}{
+		{"#/$defs/point", "Point", "point", Draft202012},
+		{"#/$defs/point/prefixItems/1", "PointPrefixItems1", "point/prefixItems/1", Draft202012},
+		{"#/$defs/measurement/unevaluatedItems", "MeasurementUnevaluatedItems", "measurement/unevaluatedItems", Draft202012},
+		{"#/$defs/person/allOf/0/properties/age", "Age", "person/allOf/0/properties/age", Draft202012},
+	}
+	for _, ts := range table {
+		s, ok := idx.Get(ts.Pointer)
+		if !ok {
+			t.Fatalf("index does not contain pointer %v", ts.Pointer)
+		}
+		if s.Name != ts.Name || s.PointerName != ts.PointerName || s.Draft != ts.Draft {
+			t.Fatalf("schema %v should be named %v, %v of draft %v but is %v, %v of draft %v",
+				ts.Pointer, ts.Name, ts.PointerName, ts.Draft, s.Name, s.PointerName, s.Draft)
+		}
+	}
+
+	inst, err := idx.schemas["#/$defs/point"].NewInstance(idx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(inst) != "[3.14 3.14]" {
+		t.Fatalf("instance of a tuple should have an item per prefixItem but is %v", inst)
+	}
+
+	idx, err = Parse([]byte(fixture.TestSchemaDraft7))
+	if err != nil {
+		panic(err)
+	}
+	if s := idx.schemas["#/definitions/point/items/0"]; s == nil || s.Name != "PointItems0" {
+		t.Fatalf("index should contain tuple item #/definitions/point/items/0 named PointItems0")
+	}
+	if _, ok := idx.Get("#/definitions/names/prefixItems/0"); ok {
+		t.Fatalf("index should not contain prefixItems of draft-07")
+	}
+}
+
+func TestWithEscapedPointers(t *testing.T) {
+	idx, err := Parse([]byte(fixture.TestSchemaEscapedPointers))
+	if err != nil {
+		panic(err)
+	}
+
+	table := []struct {
+		Pointer  string
+		Name     string
+		JSONName string
+		Ref      string
+	}{
+		{"#/definitions/a~1b", "Ab", "a/b", ""},
+		{"#/definitions/x~0y", "Xy", "x~y", ""},
+		{"#/definitions/file/properties/mime~1type", "Mimetype", "mime/type", "#/definitions/a~1b"},
+		{"#/definitions/file/properties/size~0bytes", "Sizebytes", "size~bytes", "#/definitions/x~0y"},
+		{"#/definitions/file/properties/display%20name", "Displayname", "display name", "#/definitions/a~1b"},
+	}
+	for _, ts := range table {
+		s, ok := idx.Get(ts.Pointer)
+		if !ok {
+			t.Fatalf("index does not contain pointer %v", ts.Pointer)
+		}
+		if s.Name != ts.Name || s.JSONName != ts.JSONName || s.Ref != ts.Ref {
+			t.Fatalf("schema %v should be named %v, %v with ref %v but is %v, %v with ref %v",
+				ts.Pointer, ts.Name, ts.JSONName, ts.Ref, s.Name, s.JSONName, s.Ref)
+		}
+	}
+}
+
+func TestWithTypeInference(t *testing.T) {
+	idx, err := Parse([]byte(fixture.TestSchemaTypeInference))
+	if err != nil {
+		panic(err)
+	}
+
+	table := []struct {
+		Pointer      string
+		Type         string
+		TypeInferred bool
+		Any          bool
+	}{
+		{"#/definitions/document", "object", true, false},
+		{"#/definitions/document/properties/id", "string", false, false},
+		{"#/definitions/document/properties/meta", "", false, true},
+		{"#/definitions/document/properties/extra", "", false, true},
+		{"#/definitions/document/properties/kind", "", false, false},
+		{"#/definitions/document/properties/tags", "array", true, false},
+		{"#/definitions/document/properties/author", "object", true, false},
+	}
+	for _, ts := range table {
+		s := idx.schemas[ts.Pointer]
+		if s.Type != ts.Type || s.TypeInferred != ts.TypeInferred || s.IsAny() != ts.Any {
+			t.Fatalf("schema %v should be of type %v (inferred %v, any %v) but is of type %v (inferred %v, any %v)",
+				ts.Pointer, ts.Type, ts.TypeInferred, ts.Any, s.Type, s.TypeInferred, s.IsAny())
+		}
+	}
+
+	// inferred types are not validated
+	err = idx.ValidateJSON("#/definitions/document/properties/author", []byte(`"John Snow"`))
+	if err != nil {
+		t.Fatalf("a string should be valid against a schema with inferred type object but is not: %v", err)
+	}
+
+	inst, err := idx.schemas["#/definitions/document"].NewInstance(idx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jsn, err := json.Marshal(inst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	o := `{"author":{"name":"string"},"extra":null,"id":"string","kind":"draft","meta":null,"tags":["string"]}`
+	if string(jsn) != o {
+		t.Fatalf("instance should be '%s' but is '%s'", o, jsn)
+	}
+}
+
+func TestWithBooleanSchemas(t *testing.T) {
+	idx, err := Parse([]byte(fixture.TestSchemaBooleanSchemas))
+	if err != nil {
+		panic(err)
+	}
+
+	table := map[string]bool{
+		"#/definitions/always":                                true,
+		"#/definitions/never":                                 false,
+		"#/definitions/movie/properties/legacy":               false,
+		"#/definitions/movie/properties/meta":                 true,
+		"#/definitions/movie/properties/tags/items":           true,
+		"#/definitions/movie/properties/credits/items":        false,
+		"#/definitions/movie/properties/pair/items/1":         true,
+		"#/definitions/movie/properties/pair/additionalItems": false,
+	}
+	for p, b := range table {
+		s, ok := idx.Get(p)
+		if !ok {
+			t.Fatalf("index does not contain boolean schema %v", p)
+		}
+		if s.Boolean == nil || *s.Boolean != b || s.IsAny() != b {
+			t.Fatalf("schema %v should be the boolean schema %v", p, b)
+		}
+	}
+
+	inst, err := idx.schemas["#/definitions/movie"].NewInstance(idx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = idx.schemas["#/definitions/movie"].Validate(idx, inst)
+	if err != nil {
+		t.Fatalf("instance %v should be valid but is not: %v", inst, err)
+	}
+}
+
 func TestGenerateNewInstanceJSON(t *testing.T) {
 	idx, err := Parse([]byte(fixture.TestSchemaWithDefinitions))
 	if err != nil {
 		panic(err)
 	}
 
-	s := (*idx)["#/definitions/movie"]
+	s := idx.schemas["#/definitions/movie"]
 	inst, err := s.NewInstance(idx)
 	if err != nil {
 		t.Fatal(err)
@@ -193,3 +601,132 @@ func compileAndRun(code []byte) (string, error) {
 	os.RemoveAll(name)
 	return string(out), nil
 }
+
+func TestGenerateRecursiveInstanceJSON(t *testing.T) {
+	idx, err := Parse([]byte(fixture.TestSchemaRecursive))
+	if err != nil {
+		panic(err)
+	}
+
+	table := []struct {
+		Pointer string
+		Depth   int
+		JSON    string
+	}{
+		{"#/definitions/node", 1, `{"children":[{"children":[],"links":{},"name":"string"}],"links":{},"name":"string"}`},
+		{"#/definitions/tree", 1, `{"category":{"name":"string"},"root":{"children":[],"links":{},"name":"string"}}`},
+		{"#/definitions/tree", 2, `{"category":{"name":"string","parent":{"name":"string"}},"root":{"children":[{"children":[],"links":{},"name":"string"}],"links":{},"name":"string"}}`},
+	}
+	for _, ts := range table {
+		inst, err := idx.schemas[ts.Pointer].NewInstanceWithDepth(idx, ts.Depth)
+		if err != nil {
+			t.Fatal(err)
+		}
+		jsn, err := json.Marshal(inst)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(jsn) != ts.JSON {
+			t.Fatalf("instance of %v with depth %v should be '%s' but is '%s'", ts.Pointer, ts.Depth, ts.JSON, jsn)
+		}
+		err = idx.schemas[ts.Pointer].Validate(idx, inst)
+		if err != nil {
+			t.Fatalf("instance of %v should be valid but is not: %v", ts.Pointer, err)
+		}
+	}
+}
+
+func TestRecursiveRefErrors(t *testing.T) {
+	table := []struct {
+		Schema string
+		Error  string
+	}{
+		{
+			`{"definitions": {"a": {"$ref": "#/definitions/b"}, "b": {"$ref": "#/definitions/a"}}}`,
+			"jsonschema: 1:23: cyclic $ref at #/definitions/a",
+		},
+		{
+			`{"definitions": {"a": {"type": "object", "properties": {"a": {"$ref": "#/definitions/a"}}, "required": ["a"]}}}`,
+			"jsonschema: 1:23: cannot create instance of #/definitions/a within depth 1, required values are recursive",
+		},
+	}
+	for _, ts := range table {
+		idx, err := Parse([]byte(ts.Schema))
+		if err != nil {
+			panic(err)
+		}
+		_, err = idx.schemas["#/definitions/a"].NewInstance(idx)
+		if err == nil || err.Error() != ts.Error {
+			t.Fatalf("instance of %v should fail with '%v' but failed with '%v'", ts.Schema, ts.Error, err)
+		}
+	}
+}
+
+func TestParsePreservesKeywords(t *testing.T) {
+	idx, err := Parse([]byte(`{
+		"$schema": "http://json-schema.org/draft-07/schema#",
+		"definitions": {
+			"movie": {"type": "object", "$ref": "#/definitions/base", "prefixItems": [{"type": "string"}], "unevaluatedProperties": false},
+			"base": {"type": "object"}
+		}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, ok := idx.Get("#/definitions/movie")
+	if !ok {
+		t.Fatal("index should contain #/definitions/movie")
+	}
+	if s.Ref != "#/definitions/base" || s.RefKey != "#/definitions/base" || !s.IsRef() || s.Type != "object" || fmt.Sprint(s.Types) != "[object]" {
+		t.Fatalf("movie should keep its declared $ref and type besides the computed ones but has %v, %v, %v, %v", s.Ref, s.RefKey, s.Type, s.Types)
+	}
+	if len(s.PrefixItems) != 1 || s.UnevaluatedProperties == nil {
+		t.Fatalf("movie should keep keywords not defined by draft-07")
+	}
+	if _, ok := idx.Get("#/definitions/movie/prefixItems/0"); ok {
+		t.Fatalf("index should not contain prefixItems of draft-07")
+	}
+	if err := idx.ValidateJSON("#/definitions/movie", []byte(`{"title": "Alien"}`)); err != nil {
+		t.Fatalf("unevaluatedProperties of draft-07 should be ignored but failed with %v", err)
+	}
+}
+
+func TestIndexReadAPI(t *testing.T) {
+	idx, err := Parse([]byte(`{"definitions": {"b": {"$anchor": "bee", "type": "string"}, "a": {"type": "string"}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if idx.Len() != 4 {
+		t.Fatalf("index should contain 4 keys including the $anchor but contains %v", idx.Len())
+	}
+	if s, ok := idx.Get("#bee"); !ok || s.Pointer != "#/definitions/b" {
+		t.Fatalf("#bee should be an alias of #/definitions/b")
+	}
+	if _, ok := idx.Get("#/definitions/c"); ok {
+		t.Fatalf("#/definitions/c should not exist")
+	}
+
+	var keys []string
+	idx.Range(func(key string, s *Schema) bool {
+		keys = append(keys, key)
+		return true
+	})
+	if fmt.Sprint(keys) != "[# #/definitions/a #/definitions/b #bee]" {
+		t.Fatalf("range should visit keys sorted but visits %v", keys)
+	}
+	keys = nil
+	idx.Range(func(key string, s *Schema) bool {
+		keys = append(keys, key)
+		return len(keys) < 2
+	})
+	if len(keys) != 2 {
+		t.Fatalf("range should stop when fn returns false but visits %v", keys)
+	}
+
+	var empty *Index
+	if _, ok := empty.Get("#"); ok || empty.Len() != 0 {
+		t.Fatalf("nil index should be empty")
+	}
+}
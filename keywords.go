@@ -0,0 +1,176 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+)
+
+// properties of a keyword
+type keywordFlags int
+
+const (
+	// decoded into a field of Schema, all other keywords are kept as raw values
+	fieldKeyword keywordFlags = 1 << iota
+
+	// does not constrain values
+	annotationKeyword
+
+	// holds a subschema, a list or a map of subschemas parsed into the index
+	schemaKeyword
+)
+
+// keywords of all supported drafts in canonical order, the order written by MarshalJSON,
+// including keywords without effect on parsing and validation
+var keywordTable = []struct {
+	name  string
+	flags keywordFlags
+}{
+	// core
+	{"$schema", fieldKeyword | annotationKeyword}, {"$id", fieldKeyword | annotationKeyword},
+	{"$anchor", fieldKeyword | annotationKeyword}, {"$dynamicAnchor", fieldKeyword | annotationKeyword},
+	{"$recursiveAnchor", fieldKeyword | annotationKeyword}, {"$ref", fieldKeyword},
+	{"$dynamicRef", fieldKeyword}, {"$recursiveRef", fieldKeyword}, {"$vocabulary", 0}, {"$comment", annotationKeyword},
+
+	// annotations
+	{"title", fieldKeyword | annotationKeyword}, {"description", fieldKeyword | annotationKeyword},
+	{"default", annotationKeyword}, {"examples", annotationKeyword}, {"deprecated", annotationKeyword},
+	{"readOnly", annotationKeyword}, {"writeOnly", annotationKeyword},
+
+	// any instance
+	{"type", fieldKeyword}, {"enum", fieldKeyword}, {"const", fieldKeyword}, {"format", 0},
+
+	// numbers
+	{"multipleOf", fieldKeyword}, {"maximum", fieldKeyword}, {"exclusiveMaximum", fieldKeyword},
+	{"minimum", fieldKeyword}, {"exclusiveMinimum", fieldKeyword},
+
+	// strings
+	{"maxLength", fieldKeyword}, {"minLength", fieldKeyword}, {"pattern", fieldKeyword},
+	{"contentEncoding", 0}, {"contentMediaType", 0}, {"contentSchema", 0},
+
+	// arrays
+	{"prefixItems", fieldKeyword | schemaKeyword}, {"items", fieldKeyword | schemaKeyword},
+	{"additionalItems", fieldKeyword | schemaKeyword}, {"maxItems", fieldKeyword}, {"minItems", fieldKeyword},
+	{"uniqueItems", fieldKeyword}, {"contains", fieldKeyword | schemaKeyword}, {"maxContains", fieldKeyword},
+	{"minContains", fieldKeyword}, {"unevaluatedItems", fieldKeyword | schemaKeyword},
+
+	// objects
+	{"required", fieldKeyword}, {"properties", fieldKeyword | schemaKeyword},
+	{"patternProperties", fieldKeyword | schemaKeyword}, {"additionalProperties", fieldKeyword | schemaKeyword},
+	{"propertyNames", fieldKeyword | schemaKeyword}, {"maxProperties", fieldKeyword}, {"minProperties", fieldKeyword},
+	{"dependentRequired", fieldKeyword}, {"dependentSchemas", fieldKeyword | schemaKeyword},
+	{"dependencies", fieldKeyword | schemaKeyword}, {"unevaluatedProperties", fieldKeyword | schemaKeyword},
+
+	// composition and conditions
+	{"allOf", fieldKeyword | schemaKeyword}, {"anyOf", fieldKeyword | schemaKeyword},
+	{"oneOf", fieldKeyword | schemaKeyword}, {"not", fieldKeyword | schemaKeyword},
+	{"if", fieldKeyword | schemaKeyword}, {"then", fieldKeyword | schemaKeyword}, {"else", fieldKeyword | schemaKeyword},
+
+	// definitions
+	{"$defs", fieldKeyword | annotationKeyword | schemaKeyword},
+	{"definitions", fieldKeyword | annotationKeyword | schemaKeyword},
+}
+
+// flags of the keywords of keywordTable by name
+var knownKeywords = func() map[string]keywordFlags {
+	m := make(map[string]keywordFlags, len(keywordTable))
+	for _, k := range keywordTable {
+		m[k.name] = k.flags
+	}
+	return m
+}()
+
+// returns true for keywords decoded into a field of Schema
+func isFieldKeyword(keyword string) bool {
+	return knownKeywords[keyword]&fieldKeyword != 0
+}
+
+// returns true for keywords which do not constrain values
+func isAnnotationKeyword(keyword string) bool {
+	return knownKeywords[keyword]&annotationKeyword != 0
+}
+
+// returns true for keywords whose values are subschemas
+func isSchemaKeyword(keyword string) bool {
+	return knownKeywords[keyword]&schemaKeyword != 0
+}
+
+// returns the sorted keywords of a schema object which are not known
+func unknownKeywords(keywords map[string]json.RawMessage) []string {
+	var unknown []string
+	for k := range keywords {
+		if _, ok := knownKeywords[k]; !ok {
+			unknown = append(unknown, k)
+		}
+	}
+	sort.Strings(unknown)
+	return unknown
+}
+
+// checks the keywords of all schemas of an index, see ParseOptions
+func checkKeywords(idx *Index, opts ParseOptions) error {
+	var errs KeywordErrors
+	for _, k := range idx.keys() {
+		s := idx.schemas[k]
+		if k != s.Pointer {
+			continue
+		}
+		for _, kw := range s.unknown {
+			if opts.AllowExtensions && strings.HasPrefix(kw, "x-") {
+				continue
+			}
+			errs = append(errs, &KeywordError{
+				Pointer:    string(Pointer(s.Pointer).Append(kw)),
+				Keyword:    kw,
+				Position:   s.Position,
+				Suggestion: suggestKeyword(kw),
+			})
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// returns the known keyword closest to an unknown keyword, "" if none differs by at most a third of its characters
+func suggestKeyword(unknown string) string {
+	max := len(unknown) / 3
+	if max < 1 {
+		max = 1
+	}
+
+	suggestion := ""
+	for k := range knownKeywords {
+		d := editDistance(strings.ToLower(unknown), strings.ToLower(k))
+		if d < max || d == max && (suggestion == "" || k < suggestion) {
+			suggestion, max = k, d
+		}
+	}
+	return suggestion
+}
+
+// returns the number of inserted, deleted, substituted and transposed characters between a and b
+func editDistance(a string, b string) int {
+	d := make([][]int, len(a)+1)
+	for i := range d {
+		d[i] = make([]int, len(b)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(a)][len(b)]
+}
@@ -0,0 +1,109 @@
+package jsonschema
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseStrict(t *testing.T) {
+	table := []struct {
+		Schema string
+		Opts   ParseOptions
+		Error  string
+	}{
+		{
+			`{"definitions": {"movie": {"type": "object", "requried": ["id"]}}}`,
+			ParseOptions{},
+			"",
+		},
+		{
+			`{"definitions": {"movie": {"type": "object", "requried": ["id"]}}}`,
+			ParseOptions{Strict: true},
+			"jsonschema: 1:27: unknown keyword requried at #/definitions/movie/requried, did you mean required?",
+		},
+		{
+			`{"properties": {"id": {"tpye": "string"}}, "propertise": {}}`,
+			ParseOptions{Strict: true},
+			"jsonschema: 1:1: unknown keyword propertise at #/propertise, did you mean properties?; " +
+				"jsonschema: 1:23: unknown keyword tpye at #/properties/id/tpye, did you mean type?",
+		},
+		{
+			`{"properties": {"a/b": {"type": "string", "foo": 1}}}`,
+			ParseOptions{Strict: true},
+			"jsonschema: 1:24: unknown keyword foo at #/properties/a~1b/foo",
+		},
+		{
+			`{"type": "object", "x-go-type": "Movie", "format": "movie", "$comment": "ok"}`,
+			ParseOptions{Strict: true},
+			"jsonschema: 1:1: unknown keyword x-go-type at #/x-go-type",
+		},
+		{
+			`{"type": "object", "x-go-type": "Movie", "format": "movie", "$comment": "ok"}`,
+			ParseOptions{Strict: true, AllowExtensions: true},
+			"",
+		},
+	}
+	for _, ts := range table {
+		_, err := ParseWithOptions([]byte(ts.Schema), ts.Opts)
+		if ts.Error == "" && err != nil {
+			t.Fatalf("%v should parse but failed with %v", ts.Schema, err)
+		}
+		if ts.Error != "" && (err == nil || err.Error() != ts.Error) {
+			t.Fatalf("%v should fail with '%v' but failed with '%v'", ts.Schema, ts.Error, err)
+		}
+	}
+
+	_, err := ParseWithOptions([]byte(`{"properties": {"id": {"tpye": "string"}}}`), ParseOptions{Strict: true})
+	errs, ok := err.(KeywordErrors)
+	if !ok || len(errs) != 1 {
+		t.Fatalf("strict parsing should fail with KeywordErrors but failed with %#v", err)
+	}
+	if e := errs[0]; e.Pointer != "#/properties/id/tpye" || e.Keyword != "tpye" || e.Suggestion != "type" {
+		t.Fatalf("keyword error should locate tpye at #/properties/id/tpye but is %#v", e)
+	}
+}
+
+func TestParseWithLoaderStrict(t *testing.T) {
+	loader := MapLoader{
+		"schema.json": []byte(`{"properties": {"address": {"$ref": "common.json#/definitions/address"}}}`),
+		"common.json": []byte(`{"definitions": {"address": {"type": "object", "minProperty": 1}}}`),
+	}
+	_, err := ParseWithLoaderOptions("schema.json", loader, ParseOptions{Strict: true})
+	msg := "jsonschema: common.json:1:29: unknown keyword minProperty at common.json#/definitions/address/minProperty, did you mean minProperties?"
+	if err == nil || err.Error() != msg {
+		t.Fatalf("strict parsing should fail with '%v' but failed with '%v'", msg, err)
+	}
+}
+
+func TestKnownKeywords(t *testing.T) {
+	typ := reflect.TypeOf(Schema{})
+	for i := 0; i < typ.NumField(); i++ {
+		name, _, _ := strings.Cut(typ.Field(i).Tag.Get("json"), ",")
+		switch name {
+		case "", "-", "pointer", "name", "jsonName":
+			continue
+		}
+		if !isFieldKeyword(name) {
+			t.Fatalf("keyword %v of field %v is not known as field keyword", name, typ.Field(i).Name)
+		}
+	}
+}
+
+func TestSuggestKeyword(t *testing.T) {
+	table := map[string]string{
+		"requried":           "required",
+		"Required":           "required",
+		"propertise":         "properties",
+		"tpye":               "type",
+		"minimun":            "minimum",
+		"additionalProperty": "additionalProperties",
+		"foo":                "",
+		"x-go-type":          "",
+	}
+	for kw, s := range table {
+		if suggestion := suggestKeyword(kw); suggestion != s {
+			t.Fatalf("suggestion for %v should be '%v' but is '%v'", kw, s, suggestion)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+/*
+Package lint checks parsed JSON Schema documents for likely mistakes.
+
+Rules run over a jsonschema.Index and report Diagnostics located by the JSON pointer of the offending schema:
+
+	idx, err := jsonschema.Parse(schema)
+	if err != nil {
+		panic(err)
+	}
+	for _, d := range lint.Lint(idx) {
+		fmt.Println(d)
+	}
+	// 3:14: #/definitions/movie: required property title is not defined in properties (required-property)
+
+Each rule can be disabled by its name:
+
+	ds := lint.LintWithOptions(idx, lint.Options{Disable: []string{lint.MissingDescription}})
+
+Rules
+
+required-property: names of required of object schemas which are neither defined in properties, including those of allOf,
+nor kept by patternProperties or additionalProperties
+
+dangling-ref: $ref to a schema which does not exist in the index
+
+unreachable-definition: definitions which are not referenced from the document root,
+all definitions of a root without other keywords are reachable
+
+conflicting-bounds: lower bounds greater than upper bounds, e.g. minimum 10 with maximum 5
+
+invalid-pattern: pattern and patternProperties which do not compile as regular expression
+
+missing-description: definitions of the document root without description
+
+name-collision: object, array and enum schemas whose generated names collide, e.g. a-b and a_b
+*/
+package lint
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/tfkhsr/jsonschema"
+)
+
+// Names of the rules
+const (
+	RequiredProperty      = "required-property"
+	DanglingRef           = "dangling-ref"
+	UnreachableDefinition = "unreachable-definition"
+	ConflictingBounds     = "conflicting-bounds"
+	InvalidPattern        = "invalid-pattern"
+	MissingDescription    = "missing-description"
+	NameCollision         = "name-collision"
+)
+
+// A Diagnostic describes a likely mistake in a schema document
+type Diagnostic struct {
+	// JSON pointer of the offending schema or keyword, see jsonschema.Pointer
+	Pointer string
+
+	// Position of the offending schema, or of the schema of the offending keyword
+	Position jsonschema.Position
+
+	// Name of the reporting rule, e.g. required-property
+	Rule string
+
+	// Human readable description of the mistake
+	Message string
+}
+
+func (d Diagnostic) String() string {
+	if d.Position.IsValid() {
+		return fmt.Sprintf("%v: %v: %v (%v)", d.Position, d.Pointer, d.Message, d.Rule)
+	}
+	return fmt.Sprintf("%v: %v (%v)", d.Pointer, d.Message, d.Rule)
+}
+
+// A Rule checks all schemas of an index
+type Rule struct {
+	// Name identifying the rule, e.g. required-property
+	Name string
+
+	// Check reports the diagnostics of the rule for an index
+	Check func(idx *jsonschema.Index) []Diagnostic
+}
+
+// Rules run by Lint, in order
+var Rules = []Rule{
+	{RequiredProperty, checkRequiredProperties},
+	{DanglingRef, checkDanglingRefs},
+	{UnreachableDefinition, checkUnreachableDefinitions},
+	{ConflictingBounds, checkConflictingBounds},
+	{InvalidPattern, checkPatterns},
+	{MissingDescription, checkDescriptions},
+	{NameCollision, checkNameCollisions},
+}
+
+// Options control the rules run by LintWithOptions
+type Options struct {
+	// Names of rules not to run, e.g. missing-description
+	Disable []string
+}
+
+// Lint runs all Rules over an index and returns their diagnostics sorted by pointer
+func Lint(idx *jsonschema.Index) []Diagnostic {
+	return LintWithOptions(idx, Options{})
+}
+
+// LintWithOptions runs all Rules not disabled by opts over an index and returns their diagnostics sorted by pointer
+func LintWithOptions(idx *jsonschema.Index, opts Options) []Diagnostic {
+	disabled := map[string]bool{}
+	for _, name := range opts.Disable {
+		disabled[name] = true
+	}
+
+	var ds []Diagnostic
+	for _, r := range Rules {
+		if disabled[r.Name] {
+			continue
+		}
+		for _, d := range r.Check(idx) {
+			d.Rule = r.Name
+			d.Position = position(idx, jsonschema.Pointer(d.Pointer))
+			ds = append(ds, d)
+		}
+	}
+	sort.SliceStable(ds, func(i, j int) bool {
+		return ds[i].Pointer < ds[j].Pointer
+	})
+	return ds
+}
+
+// returns the schemas of an index sorted by pointer, omitting the aliases of $id and $anchor
+func schemas(idx *jsonschema.Index) []*jsonschema.Schema {
+	var ss []*jsonschema.Schema
+	idx.Range(func(k string, s *jsonschema.Schema) bool {
+		if k == s.Pointer {
+			ss = append(ss, s)
+		}
+		return true
+	})
+	return ss
+}
+
+// returns the position of the schema at a pointer or of its nearest parent schema
+func position(idx *jsonschema.Index, ptr jsonschema.Pointer) jsonschema.Position {
+	for {
+		if s, ok := idx.Get(string(ptr)); ok {
+			return s.Position
+		}
+		if ptr.Parent() == ptr {
+			return jsonschema.Position{}
+		}
+		ptr = ptr.Parent()
+	}
+}
+
+// returns the keys of a map of schemas sorted by alphabet
+func sortedKeys(m jsonschema.Schemas) []string {
+	var keys []string
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
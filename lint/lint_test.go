@@ -0,0 +1,105 @@
+package lint
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tfkhsr/jsonschema"
+	"github.com/tfkhsr/jsonschema/fixture"
+)
+
+func TestLint(t *testing.T) {
+	table := []struct {
+		Schema      string
+		Diagnostics []string
+	}{
+		{
+			`{"definitions": {"movie": {"description": "a movie", "type": "object", "properties": {"id": {"type": "string"}}, "required": ["id", "title"]}}}`,
+			[]string{"1:27: #/definitions/movie: required property title is not defined in properties (required-property)"},
+		},
+		{
+			`{"definitions": {"movie": {"description": "a movie", "allOf": [{"$ref": "#/definitions/base"}], "properties": {"title": {"type": "string"}}, "required": ["id", "title"]},
+				"base": {"description": "a base", "properties": {"id": {"type": "string"}}}}}`,
+			nil,
+		},
+		{
+			`{"definitions": {"movie": {"description": "a movie", "type": "object", "required": ["id"]},
+				"tags": {"description": "tags", "type": "object", "additionalProperties": {"type": "string"}, "required": ["main"]},
+				"key": {"description": "a key", "anyOf": [{"required": ["k"]}, {"required": ["j"]}]}}}`,
+			[]string{"1:27: #/definitions/movie: required property id is not defined in properties (required-property)"},
+		},
+		{
+			`{"definitions": {"movie": {"description": "a movie", "properties": {"actor": {"$ref": "#/definitions/actor"}}}}}`,
+			[]string{"1:78: #/definitions/movie/properties/actor: $ref #/definitions/actor does not exist (dangling-ref)"},
+		},
+		{
+			`{"$ref": "#/definitions/movie", "definitions": {
+				"movie": {"description": "a movie", "properties": {"actor": {"$ref": "#/definitions/actor"}}},
+				"actor": {"description": "an actor", "type": "object"},
+				"studio": {"description": "a studio", "type": "object", "definitions": {"city": {"type": "string"}}}}}`,
+			[]string{
+				"4:15: #/definitions/studio: definition is not referenced (unreachable-definition)",
+				"4:85: #/definitions/studio/definitions/city: definition is not referenced (unreachable-definition)",
+			},
+		},
+		{
+			`{"definitions": {"movie": {"description": "a movie", "type": "object", "definitions": {"id": {"type": "string"}}}}}`,
+			[]string{"1:94: #/definitions/movie/definitions/id: definition is not referenced (unreachable-definition)"},
+		},
+		{
+			`{"definitions": {"year": {"description": "a year", "type": "integer", "minimum": 2000, "maximum": 1900},
+				"rating": {"description": "a rating", "type": "number", "exclusiveMinimum": 5, "maximum": 5},
+				"title": {"description": "a title", "type": "string", "minLength": 10, "maxLength": 1},
+				"tags": {"description": "tags", "type": "array", "minItems": 3, "maxItems": 2}}}`,
+			[]string{
+				"2:15: #/definitions/rating: exclusiveMinimum 5 conflicts with maximum 5 (conflicting-bounds)",
+				"4:13: #/definitions/tags: minItems 3 conflicts with maxItems 2 (conflicting-bounds)",
+				"3:14: #/definitions/title: minLength 10 conflicts with maxLength 1 (conflicting-bounds)",
+				"1:26: #/definitions/year: minimum 2000 conflicts with maximum 1900 (conflicting-bounds)",
+			},
+		},
+		{
+			`{"definitions": {"id": {"description": "an id", "type": "string", "pattern": "^[a-z+$"},
+				"labels": {"description": "labels", "type": "object", "patternProperties": {"^(x": {"type": "string"}}}}}`,
+			[]string{
+				"1:24: #/definitions/id/pattern: pattern ^[a-z+$ is invalid: missing closing ]: `[a-z+$` (invalid-pattern)",
+				"2:88: #/definitions/labels/patternProperties/%5E(x: pattern ^(x is invalid: missing closing ): `^(x` (invalid-pattern)",
+			},
+		},
+		{
+			`{"definitions": {"movie": {"type": "object"}, "never": false}}`,
+			[]string{"1:27: #/definitions/movie: definition Movie has no description (missing-description)"},
+		},
+		{
+			`{"definitions": {"movie-tag": {"description": "a tag", "enum": ["new"]}, "movie_tag": {"description": "a tag", "enum": ["old"]},
+				"movie": {"description": "a movie", "type": "object", "properties": {"movie_tag": {"type": "string"}}}}}`,
+			[]string{"1:87: #/definitions/movie_tag: name Movietag collides with #/definitions/movie-tag (name-collision)"},
+		},
+	}
+	for _, ts := range table {
+		idx, err := jsonschema.Parse([]byte(ts.Schema))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ds []string
+		for _, d := range Lint(idx) {
+			ds = append(ds, d.String())
+		}
+		if strings.Join(ds, "\n") != strings.Join(ts.Diagnostics, "\n") {
+			t.Fatalf("diagnostics of %v should be\n%v\nbut are\n%v", ts.Schema, strings.Join(ts.Diagnostics, "\n"), strings.Join(ds, "\n"))
+		}
+	}
+}
+
+func TestLintWithOptions(t *testing.T) {
+	idx, err := jsonschema.Parse([]byte(fixture.TestSchemaWithDefinitions))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ds := Lint(idx); len(ds) == 0 || ds[0].Rule != MissingDescription {
+		t.Fatalf("definitions of fixture should miss descriptions but diagnostics are %v", ds)
+	}
+	if ds := LintWithOptions(idx, Options{Disable: []string{MissingDescription}}); len(ds) != 0 {
+		t.Fatalf("fixture should have no diagnostics besides missing descriptions but has %v", ds)
+	}
+}
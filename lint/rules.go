@@ -0,0 +1,262 @@
+package lint
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/tfkhsr/jsonschema"
+)
+
+// reports required names of object schemas which are neither defined in properties, including those of allOf,
+// nor kept as additional properties, i.e. names the generated go types cannot check
+func checkRequiredProperties(idx *jsonschema.Index) []Diagnostic {
+	var ds []Diagnostic
+	for _, s := range schemas(idx) {
+		if s.IsRef() || s.Type != "object" || keepsAdditionalProperties(s) {
+			continue
+		}
+		props := objectProperties(s, idx, map[*jsonschema.Schema]bool{})
+		for _, name := range s.Required {
+			if !props[name] {
+				ds = append(ds, Diagnostic{
+					Pointer: s.Pointer,
+					Message: fmt.Sprintf("required property %v is not defined in properties", name),
+				})
+			}
+		}
+	}
+	return ds
+}
+
+// returns the names of the properties of an object schema and of the object schemas of its allOf
+func objectProperties(s *jsonschema.Schema, idx *jsonschema.Index, seen map[*jsonschema.Schema]bool) map[string]bool {
+	props := map[string]bool{}
+	if seen[s] {
+		return props
+	}
+	seen[s] = true
+	for name := range s.Properties {
+		props[name] = true
+	}
+	for _, a := range s.AllOf {
+		r, err := idx.Resolve(a)
+		if err != nil || r.Type != "object" || keepsAdditionalProperties(r) {
+			continue
+		}
+		for name := range objectProperties(r, idx, seen) {
+			props[name] = true
+		}
+	}
+	return props
+}
+
+// returns true if an object schema keeps the values of properties not defined in properties,
+// i.e. it has patternProperties, an additionalProperties schema or additionalProperties true without properties
+func keepsAdditionalProperties(s *jsonschema.Schema) bool {
+	ap := s.AdditionalProperties
+	switch {
+	case len(s.PatternProperties) > 0 || (ap != nil && ap.Boolean == nil):
+		return true
+	case ap != nil && *ap.Boolean:
+		return len(s.Properties) == 0 && len(s.AllOf) == 0
+	}
+	return false
+}
+
+// reports references to schemas which do not exist in the index
+func checkDanglingRefs(idx *jsonschema.Index) []Diagnostic {
+	var ds []Diagnostic
+	for _, s := range schemas(idx) {
+		if s.Ref == "" {
+			continue
+		}
+		if _, ok := idx.Get(s.RefKey); !ok {
+			ds = append(ds, Diagnostic{
+				Pointer: s.Pointer,
+				Message: fmt.Sprintf("$ref %v does not exist", s.Ref),
+			})
+		}
+	}
+	return ds
+}
+
+// reports definitions which are not reachable from the document root through subschemas and references
+func checkUnreachableDefinitions(idx *jsonschema.Index) []Diagnostic {
+	root, ok := idx.Get("#")
+	if !ok {
+		return nil
+	}
+
+	// the definitions of a root without other keywords are the entry points of the document
+	roots := []*jsonschema.Schema{root}
+	if root.IsAny() {
+		roots = definitions(root)
+	}
+
+	reached := map[*jsonschema.Schema]bool{}
+	var reach func(s *jsonschema.Schema)
+	reach = func(s *jsonschema.Schema) {
+		if s == nil || reached[s] {
+			return
+		}
+		reached[s] = true
+		if s.IsRef() {
+			ref, _ := idx.Get(s.RefKey)
+			reach(ref)
+		}
+		for _, sch := range subschemas(s) {
+			reach(sch)
+		}
+	}
+	for _, s := range roots {
+		reach(s)
+	}
+
+	var ds []Diagnostic
+	for _, s := range schemas(idx) {
+		for _, d := range definitions(s) {
+			if !reached[d] {
+				ds = append(ds, Diagnostic{
+					Pointer: d.Pointer,
+					Message: "definition is not referenced",
+				})
+			}
+		}
+	}
+	return ds
+}
+
+// reports lower bounds greater than upper bounds
+func checkConflictingBounds(idx *jsonschema.Index) []Diagnostic {
+	var ds []Diagnostic
+	conflict := func(s *jsonschema.Schema, lower string, l float64, upper string, u float64, equal bool) {
+		if l > u || equal && l == u {
+			ds = append(ds, Diagnostic{
+				Pointer: s.Pointer,
+				Message: fmt.Sprintf("%v %v conflicts with %v %v", lower, l, upper, u),
+			})
+		}
+	}
+	ints := func(s *jsonschema.Schema, lower string, l *int, upper string, u *int) {
+		if l != nil && u != nil {
+			conflict(s, lower, float64(*l), upper, float64(*u), false)
+		}
+	}
+
+	for _, s := range schemas(idx) {
+		for _, l := range []struct {
+			name      string
+			v         *float64
+			exclusive bool
+		}{{"minimum", s.Minimum, false}, {"exclusiveMinimum", s.ExclusiveMinimum, true}} {
+			for _, u := range []struct {
+				name      string
+				v         *float64
+				exclusive bool
+			}{{"maximum", s.Maximum, false}, {"exclusiveMaximum", s.ExclusiveMaximum, true}} {
+				if l.v != nil && u.v != nil {
+					conflict(s, l.name, *l.v, u.name, *u.v, l.exclusive || u.exclusive)
+				}
+			}
+		}
+		ints(s, "minLength", s.MinLength, "maxLength", s.MaxLength)
+		ints(s, "minItems", s.MinItems, "maxItems", s.MaxItems)
+		ints(s, "minContains", s.MinContains, "maxContains", s.MaxContains)
+		ints(s, "minProperties", s.MinProperties, "maxProperties", s.MaxProperties)
+	}
+	return ds
+}
+
+// reports pattern and patternProperties which are no valid regular expressions
+func checkPatterns(idx *jsonschema.Index) []Diagnostic {
+	var ds []Diagnostic
+	invalid := func(ptr jsonschema.Pointer, pattern string) {
+		if _, err := regexp.Compile(pattern); err != nil {
+			ds = append(ds, Diagnostic{
+				Pointer: string(ptr),
+				Message: fmt.Sprintf("pattern %v is invalid: %v", pattern, strings.TrimPrefix(err.Error(), "error parsing regexp: ")),
+			})
+		}
+	}
+
+	for _, s := range schemas(idx) {
+		if s.Pattern != "" {
+			invalid(jsonschema.Pointer(s.Pointer).Append("pattern"), s.Pattern)
+		}
+		for _, p := range sortedKeys(s.PatternProperties) {
+			invalid(jsonschema.Pointer(s.Pointer).Append("patternProperties").Append(p), p)
+		}
+	}
+	return ds
+}
+
+// reports definitions of the document root without description
+func checkDescriptions(idx *jsonschema.Index) []Diagnostic {
+	root, ok := idx.Get("#")
+	if !ok {
+		return nil
+	}
+
+	var ds []Diagnostic
+	for _, d := range definitions(root) {
+		if d.Description == "" && d.Boolean == nil {
+			ds = append(ds, Diagnostic{
+				Pointer: d.Pointer,
+				Message: fmt.Sprintf("definition %v has no description", d.Name),
+			})
+		}
+	}
+	return ds
+}
+
+// reports object, array and enum schemas of the same name, which result in colliding generated types
+func checkNameCollisions(idx *jsonschema.Index) []Diagnostic {
+	byName := map[string][]*jsonschema.Schema{}
+	for _, s := range schemas(idx) {
+		if s.Name == "" || (s.IsRef() || s.Type != "object" && s.Type != "array") && len(s.Enum) == 0 {
+			continue
+		}
+		byName[s.Name] = append(byName[s.Name], s)
+	}
+
+	var ds []Diagnostic
+	for _, ss := range byName {
+		if len(ss) < 2 {
+			continue
+		}
+		for _, s := range ss[1:] {
+			ds = append(ds, Diagnostic{
+				Pointer: s.Pointer,
+				Message: fmt.Sprintf("name %v collides with %v", s.Name, ss[0].Pointer),
+			})
+		}
+	}
+	return ds
+}
+
+// returns the definitions and $defs of a schema sorted by name
+func definitions(s *jsonschema.Schema) []*jsonschema.Schema {
+	var ds []*jsonschema.Schema
+	for _, m := range []jsonschema.Schemas{s.Definitions, s.Defs} {
+		for _, k := range sortedKeys(m) {
+			ds = append(ds, m[k])
+		}
+	}
+	return ds
+}
+
+// returns all subschemas of a schema applying to instances, i.e. all besides definitions
+func subschemas(s *jsonschema.Schema) []*jsonschema.Schema {
+	ss := []*jsonschema.Schema{s.Items, s.AdditionalItems, s.UnevaluatedItems, s.UnevaluatedProperties,
+		s.AdditionalProperties, s.Not, s.Contains, s.PropertyNames, s.If, s.Then, s.Else}
+	for _, l := range [][]*jsonschema.Schema{s.ItemsList, s.PrefixItems, s.AllOf, s.AnyOf, s.OneOf} {
+		ss = append(ss, l...)
+	}
+	for _, m := range []jsonschema.Schemas{s.Properties, s.PatternProperties, s.DependentSchemas, s.Dependencies} {
+		for _, k := range sortedKeys(m) {
+			ss = append(ss, m[k])
+		}
+	}
+	return ss
+}
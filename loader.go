@@ -0,0 +1,313 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// A Loader loads the raw schema document of a URI without fragment
+type Loader interface {
+	Load(uri string) ([]byte, error)
+}
+
+// FSLoader loads documents from a file system by the path of their URI
+type FSLoader struct {
+	FS fs.FS
+}
+
+// Load reads the file at the path of uri, relative to the root of the file system
+func (l FSLoader) Load(uri string) ([]byte, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "" && u.Scheme != "file" {
+		return nil, fmt.Errorf("jsonschema: unsupported scheme %v of %v", u.Scheme, uri)
+	}
+	return fs.ReadFile(l.FS, strings.TrimPrefix(u.Path, "/"))
+}
+
+// MapLoader loads documents from memory by their URI
+type MapLoader map[string][]byte
+
+// Load returns the document of uri
+func (l MapLoader) Load(uri string) ([]byte, error) {
+	b, ok := l[uri]
+	if !ok {
+		return nil, fmt.Errorf("jsonschema: document %v does not exist", uri)
+	}
+	return b, nil
+}
+
+// HTTPLoader loads documents of http and https URIs
+type HTTPLoader struct {
+	// Client used for requests, http.DefaultClient if nil
+	Client *http.Client
+}
+
+// Load requests the document of uri
+func (l HTTPLoader) Load(uri string) ([]byte, error) {
+	c := l.Client
+	if c == nil {
+		c = http.DefaultClient
+	}
+	res, err := c.Get(uri)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("jsonschema: loading %v failed with status %v", uri, res.Status)
+	}
+	return io.ReadAll(res.Body)
+}
+
+// ParseWithLoader loads the schema document at uri and all documents referenced by it into an Index.
+//
+// Schemas of the document at uri are indexed by their JSON pointer as with Parse, schemas of all other
+// documents by their URI followed by their JSON pointer, e.g. common.json#/definitions/address.
+// References are resolved against the base URI of their schema and rewritten to these keys,
+// so resolving a Schema.Ref is a lookup of its Schema.RefKey in the Index.
+func ParseWithLoader(uri string, loader Loader) (*Index, error) {
+	return ParseWithLoaderOptions(uri, loader, ParseOptions{})
+}
+
+// ParseWithLoaderOptions loads the schema document at uri and all documents referenced by it into an Index
+// using the given options, see ParseWithLoader
+func ParseWithLoaderOptions(uri string, loader Loader, opts ParseOptions) (*Index, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, fmt.Errorf("jsonschema: invalid uri %v: %v", uri, err)
+	}
+	u.Fragment = ""
+
+	l := newDocumentLoader(u.String(), loader)
+	l.validate = opts.ValidateDocument
+	err = l.load(l.root)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkRefs(l.idx)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Strict {
+		err = checkKeywords(l.idx, opts)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return l.idx, nil
+}
+
+// loads documents into a single index
+type documentLoader struct {
+	// Loader of referenced documents, nil to keep references to other documents
+	loader Loader
+
+	// URI of the document indexed without URI
+	root string
+
+	idx *Index
+
+	// index keys of the schemas by the base URIs they establish
+	bases map[string]string
+
+	// validate loaded documents against their meta-schema, see ParseOptions
+	validate bool
+}
+
+// creates a loader of the document at root
+func newDocumentLoader(root string, loader Loader) *documentLoader {
+	return &documentLoader{
+		loader: loader,
+		root:   root,
+		idx:    newIndex(),
+		bases:  map[string]string{},
+	}
+}
+
+// loads the document at uri and all documents referenced by it, loading each document once
+func (l *documentLoader) load(uri string) error {
+	if _, ok := l.bases[uri]; ok {
+		return nil
+	}
+
+	b, err := l.loader.Load(uri)
+	if err != nil {
+		return fmt.Errorf("jsonschema: cannot load %v: %v", uri, err)
+	}
+	if l.validate {
+		err = ValidateSchemaDocument(b)
+		if err != nil {
+			return err
+		}
+	}
+	return l.add(uri, uri, b)
+}
+
+// indexes the schemas of the document b at uri including the aliases of $id and $anchor,
+// locates them in file and resolves their references
+func (l *documentLoader) add(uri string, file string, b []byte) error {
+	var s Schema
+	err := json.Unmarshal(b, &s)
+	if err != nil {
+		return decodeError(b, file, err)
+	}
+	root := Pointer(l.key(uri, ""))
+	positions, err := scanPositions(b, file, root)
+	if err != nil {
+		return decodeError(b, file, err)
+	}
+
+	if ptr, ok := s.nullSubschema(root); ok {
+		return fmt.Errorf("jsonschema: %v: schema at %v must be an object or a boolean, not null", positions[ptr], ptr)
+	}
+
+	doc := newIndex()
+	s.parse(doc, root, uri, DraftAny)
+
+	keys := doc.keys()
+	l.bases[uri] = l.key(uri, "")
+	for _, k := range keys {
+		sch := doc.schemas[k]
+		sch.Position = positions[Pointer(k)]
+		l.idx.schemas[k] = sch
+		if sch.ID != "" {
+			if _, ok := l.bases[sch.BaseURI]; !ok {
+				l.bases[sch.BaseURI] = k
+			}
+		}
+	}
+
+	// resources of $dynamicRef and $recursiveRef
+	for _, k := range keys {
+		sch := doc.schemas[k]
+		sch.resource = l.idx.schemas[l.bases[sch.BaseURI]]
+		if sch.DynamicAnchor != "" && sch.Draft.defines("$dynamicAnchor") && sch.resource != nil {
+			if sch.resource.dynamicAnchors == nil {
+				sch.resource.dynamicAnchors = map[string]*Schema{}
+			}
+			sch.resource.dynamicAnchors[sch.DynamicAnchor] = sch
+		}
+	}
+
+	// URIs of $id and anchors of draft-07 $id fragments, $anchor and $dynamicAnchor
+	for _, k := range keys {
+		sch := doc.schemas[k]
+		if u, fragment, err := resolveURI(sch.BaseURI, sch.ID); err == nil && sch.ID != "" && fragment == "" && u != "" {
+			if _, ok := l.idx.schemas[u]; !ok {
+				l.idx.schemas[u] = sch
+			}
+		}
+		anchors := []string{sch.Anchor}
+		if sch.Draft.defines("$dynamicAnchor") {
+			anchors = append(anchors, sch.DynamicAnchor)
+		}
+		if _, fragment, err := resolveURI(sch.BaseURI, sch.ID); err == nil && !strings.HasPrefix(fragment, "/") {
+			anchors = append(anchors, fragment)
+		}
+		for _, a := range anchors {
+			if a != "" {
+				l.idx.schemas[l.key(sch.BaseURI, a)] = sch
+			}
+		}
+	}
+
+	for _, k := range keys {
+		sch := doc.schemas[k]
+		for _, r := range []struct {
+			keyword string
+			ref     string
+			key     *string
+		}{{"$ref", sch.Ref, &sch.RefKey}, {"$recursiveRef", sch.RecursiveRef, &sch.recursiveRefKey}, {"$dynamicRef", sch.DynamicRef, &sch.dynamicRefKey}} {
+			if r.ref == "" || !sch.Draft.defines(r.keyword) {
+				continue
+			}
+			key, err := l.resolveRef(sch, r.keyword, r.ref)
+			if err != nil {
+				return err
+			}
+			*r.key = key
+		}
+	}
+	return nil
+}
+
+// resolves a reference of a schema against its base URI to an index key, loading the referenced document
+func (l *documentLoader) resolveRef(s *Schema, keyword string, ref string) (string, error) {
+	uri, fragment, err := resolveURI(s.BaseURI, ref)
+	if err != nil {
+		return "", s.Errorf("invalid %v %v of %v: %v", keyword, ref, s.Pointer, err)
+	}
+	if _, ok := l.bases[uri]; !ok && l.loader != nil {
+		err = l.load(uri)
+		if err != nil {
+			return "", err
+		}
+	}
+	return l.refKey(uri, fragment), nil
+}
+
+// returns the index key of a JSON pointer or anchor fragment relative to the base URI ref
+func (l *documentLoader) refKey(ref string, fragment string) string {
+	base, ok := l.bases[ref]
+	if !ok {
+		return l.key(ref, fragment)
+	}
+	if fragment == "" || strings.HasPrefix(fragment, "/") {
+		return base + escapeFragment(fragment)
+	}
+	return l.key(ref, fragment)
+}
+
+// returns the index key of a decoded fragment relative to uri, omitting the URI of the root document and its $id
+func (l *documentLoader) key(uri string, fragment string) string {
+	if uri == l.root || l.bases[uri] == "#" {
+		uri = ""
+	}
+	return string(pointerFromFragment(uri, fragment))
+}
+
+// resolves a reference against a base URI, returning the resolved URI without fragment and the fragment
+func resolveURI(base string, ref string) (string, string, error) {
+	b, err := url.Parse(base)
+	if err != nil {
+		return "", "", err
+	}
+	r, err := url.Parse(ref)
+	if err != nil {
+		return "", "", err
+	}
+	u := b.ResolveReference(r)
+	fragment := u.Fragment
+	u.Fragment = ""
+
+	// keep URIs relative to the working directory of a loader relative
+	if u.Scheme == "" && u.Host == "" && !strings.HasPrefix(b.Path, "/") {
+		u.Path = strings.TrimPrefix(u.Path, "/")
+	}
+	return u.String(), fragment, nil
+}
+
+// checks that all references exist and do not only reference each other
+func checkRefs(idx *Index) error {
+	for _, k := range idx.keys() {
+		s := idx.schemas[k]
+		for _, r := range []struct{ keyword, ref, key string }{{"$recursiveRef", s.RecursiveRef, s.recursiveRefKey}, {"$dynamicRef", s.DynamicRef, s.dynamicRefKey}} {
+			if _, ok := idx.Get(r.key); r.key != "" && !ok {
+				return s.Errorf("%v %v of %v does not exist", r.keyword, r.ref, s.Pointer)
+			}
+		}
+		if _, err := idx.Resolve(s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+package jsonschema
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/tfkhsr/jsonschema/fixture"
+)
+
+func TestParseWithLoader(t *testing.T) {
+	loader := MapLoader{
+		"schemas/schema.json": []byte(fixture.TestSchemaExternalRef),
+		"schemas/common.json": []byte(fixture.TestSchemaExternalRefCommon),
+	}
+	idx, err := ParseWithLoader("schemas/schema.json", loader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	table := map[string]string{
+		"#/definitions/person/properties/address":                     "schemas/common.json#/definitions/address",
+		"schemas/common.json#/definitions/address/properties/city":    "schemas/common.json#/definitions/city",
+		"schemas/common.json#/definitions/address/properties/country": "#/definitions/country",
+	}
+	for p, ref := range table {
+		s, ok := idx.Get(p)
+		if !ok {
+			t.Fatalf("index does not contain pointer %v", p)
+		}
+		if s.RefKey != ref {
+			t.Fatalf("ref of %v is not %v but %v", p, ref, s.RefKey)
+		}
+	}
+
+	s := idx.schemas["schemas/common.json#/definitions/address"]
+	if s.Name != "Address" || s.PointerName != "address" {
+		t.Fatalf("schema of referenced document should be named Address but is named %v", s.Name)
+	}
+	if s := idx.schemas["schemas/common.json#"]; s == nil || s.Name != "Common" {
+		t.Fatalf("root of referenced document should be indexed and named Common")
+	}
+}
+
+func TestParseWithLoaderValidation(t *testing.T) {
+	loader := MapLoader{
+		"schema.json": []byte(fixture.TestSchemaExternalRef),
+		"common.json": []byte(fixture.TestSchemaExternalRefCommon),
+	}
+	idx, err := ParseWithLoader("schema.json", loader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	table := []struct {
+		Doc   string
+		Valid bool
+	}{
+		{`{"name": "John Snow", "address": {"city": "Winterfell", "country": "GB"}}`, true},
+		{`{"name": "John Snow", "address": {"country": "GB"}}`, false},
+		{`{"name": "John Snow", "address": {"city": 1}}`, false},
+		{`{"name": "John Snow", "address": {"city": "Winterfell", "country": "GBR"}}`, false},
+	}
+	for _, ts := range table {
+		err := idx.ValidateJSON("#/definitions/person", []byte(ts.Doc))
+		if ts.Valid && err != nil {
+			t.Fatalf("%v should be valid but is not: %v", ts.Doc, err)
+		}
+		if !ts.Valid && err == nil {
+			t.Fatalf("%v should be invalid but is not", ts.Doc)
+		}
+	}
+}
+
+func TestParseWithLoaderBaseURI(t *testing.T) {
+	loader := MapLoader{
+		"schema.json": []byte(`{
+			"$id": "http://example.com/schemas/schema.json",
+			"properties": {
+				"address": {"$ref": "common.json#address"}
+			}
+		}`),
+		"http://example.com/schemas/common.json": []byte(`{
+			"definitions": {
+				"address": {"$anchor": "address", "type": "object"}
+			}
+		}`),
+	}
+	idx, err := ParseWithLoader("schema.json", loader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := idx.schemas["#/properties/address"]
+	if s.Ref != "common.json#address" {
+		t.Fatalf("ref should keep its declared value but is %v", s.Ref)
+	}
+	if s.RefKey != "http://example.com/schemas/common.json#address" {
+		t.Fatalf("ref should be resolved against the $id of the document but is %v", s.RefKey)
+	}
+	if idx.schemas[s.RefKey].Pointer != "http://example.com/schemas/common.json#/definitions/address" {
+		t.Fatalf("ref %v should resolve to the anchored schema", s.RefKey)
+	}
+}
+
+func TestParseWithFSLoader(t *testing.T) {
+	fsys := fstest.MapFS{
+		"schemas/schema.json":        {Data: []byte(fixture.TestSchemaExternalRef)},
+		"schemas/common.json":        {Data: []byte(fixture.TestSchemaExternalRefCommon)},
+		"schemas/nested/nested.json": {Data: []byte(`{"$ref": "../common.json#/definitions/city"}`)},
+	}
+	idx, err := ParseWithLoader("schemas/nested/nested.json", FSLoader{FS: fsys})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range []string{"#", "schemas/common.json#/definitions/city", "schemas/schema.json#/definitions/country"} {
+		if _, ok := idx.Get(p); !ok {
+			t.Fatalf("index does not contain pointer %v", p)
+		}
+	}
+}
+
+func TestParseWithHTTPLoader(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/schemas/schema.json":
+			w.Write([]byte(fixture.TestSchemaExternalRef))
+		case "/schemas/common.json":
+			w.Write([]byte(fixture.TestSchemaExternalRefCommon))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	idx, err := ParseWithLoader(srv.URL+"/schemas/schema.json", HTTPLoader{Client: srv.Client()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := srv.URL + "/schemas/common.json#/definitions/address"
+	if _, ok := idx.Get(p); !ok {
+		t.Fatalf("index does not contain pointer %v", p)
+	}
+
+	_, err = ParseWithLoader(srv.URL+"/schemas/unknown.json", HTTPLoader{Client: srv.Client()})
+	if err == nil || !strings.Contains(err.Error(), "404") {
+		t.Fatalf("loading an unknown document should fail with status 404, but failed with %v", err)
+	}
+}
+
+func TestParseWithLoaderErrors(t *testing.T) {
+	table := []struct {
+		Docs  MapLoader
+		Error string
+	}{
+		{
+			MapLoader{"schema.json": []byte(`{"$ref": "common.json"}`)},
+			"jsonschema: cannot load common.json: jsonschema: document common.json does not exist",
+		},
+		{
+			MapLoader{"schema.json": []byte(`{"$ref": "#/definitions/unknown"}`)},
+			"jsonschema: schema.json:1:1: $ref #/definitions/unknown of # does not exist",
+		},
+		{
+			MapLoader{"schema.json": []byte(`{"type": "object", "properties": {"a": {"$ref": "common.json#/definitions/b"}}}`),
+				"common.json": []byte(`{"definitions": {"b": {"$ref": "schema.json#/properties/a"}}}`)},
+			"jsonschema: schema.json:1:40: cyclic $ref at #/properties/a",
+		},
+	}
+	for _, ts := range table {
+		_, err := ParseWithLoader("schema.json", ts.Docs)
+		if err == nil || err.Error() != ts.Error {
+			t.Fatalf("parsing %v should fail with '%v', but failed with '%v'", ts.Docs, ts.Error, err)
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// MarshalSchema encodes the document root # of the index as indented JSON with keywords in canonical order,
+// see Schema.MarshalJSON. The roots of referenced documents are encoded by their own MarshalJSON.
+func (idx *Index) MarshalSchema() ([]byte, error) {
+	root, ok := idx.Get("#")
+	if !ok {
+		return nil, fmt.Errorf("jsonschema: index has no document root #")
+	}
+	b, err := root.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	var w bytes.Buffer
+	err = json.Indent(&w, b, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return w.Bytes(), nil
+}
+
+// MarshalJSON encodes a schema as declared, with keywords in canonical order and names sorted by alphabet.
+//
+// The exported fields are written, so copies of parsed schemas can be edited before they are written back, the
+// schemas of an Index itself are read-only. Computed fields like
+// Pointer, Name, RefKey and an inferred Type are omitted: type is written from Types, or from Type if it was changed
+// after parsing or Types is empty. Const is written unless it is nil, besides a parsed const of null.
+// Keywords without a field, e.g. format, default and unknown keywords, are written back as parsed.
+// Property dependencies of the draft-07 dependencies keyword are written back to dependencies.
+func (s *Schema) MarshalJSON() ([]byte, error) {
+	if s.Boolean != nil {
+		return json.Marshal(*s.Boolean)
+	}
+	return marshalKeywords(s.keywordValues())
+}
+
+// Keywords returns the keywords of a schema in canonical order as written by MarshalJSON, nil for boolean schemas
+func (s *Schema) Keywords() []string {
+	if s.Boolean != nil {
+		return nil
+	}
+	return orderKeywords(s.keywordValues())
+}
+
+// returns the values of all keywords of a schema as written by MarshalJSON
+func (s *Schema) keywordValues() map[string]interface{} {
+	keywords := map[string]interface{}{}
+	for k, raw := range s.keywords {
+		keywords[k] = raw
+	}
+	set := func(keyword string, v interface{}, ok bool) {
+		if ok {
+			keywords[keyword] = v
+		}
+	}
+
+	set("$schema", s.SchemaURI, s.SchemaURI != "")
+	set("$id", s.ID, s.ID != "")
+	set("$anchor", s.Anchor, s.Anchor != "")
+	set("$dynamicAnchor", s.DynamicAnchor, s.DynamicAnchor != "")
+	set("$recursiveAnchor", s.RecursiveAnchor, s.RecursiveAnchor)
+	set("$ref", s.Ref, s.Ref != "")
+	set("$dynamicRef", s.DynamicRef, s.DynamicRef != "")
+	set("$recursiveRef", s.RecursiveRef, s.RecursiveRef != "")
+	set("title", s.Title, s.Title != "")
+	set("description", s.Description, s.Description != "")
+
+	switch types := s.declaredTypes(); len(types) {
+	case 0:
+	case 1:
+		keywords["type"] = types[0]
+	default:
+		keywords["type"] = types
+	}
+	set("enum", s.Enum, s.Enum != nil)
+	set("const", s.Const, s.HasConst())
+
+	for _, k := range numericKeywords {
+		n, ok := s.Number(k)
+		set(k, n, ok)
+	}
+	set("maxLength", s.MaxLength, s.MaxLength != nil)
+	set("minLength", s.MinLength, s.MinLength != nil)
+	set("pattern", s.Pattern, s.Pattern != "")
+
+	set("prefixItems", s.PrefixItems, s.PrefixItems != nil)
+	set("items", s.ItemsList, s.ItemsList != nil)
+	set("items", s.Items, s.Items != nil)
+	set("additionalItems", s.AdditionalItems, s.AdditionalItems != nil)
+	set("maxItems", s.MaxItems, s.MaxItems != nil)
+	set("minItems", s.MinItems, s.MinItems != nil)
+	set("uniqueItems", s.UniqueItems, s.UniqueItems)
+	set("contains", s.Contains, s.Contains != nil)
+	set("maxContains", s.MaxContains, s.MaxContains != nil)
+	set("minContains", s.MinContains, s.MinContains != nil)
+	set("unevaluatedItems", s.UnevaluatedItems, s.UnevaluatedItems != nil)
+
+	set("required", s.Required, s.Required != nil)
+	set("properties", s.Properties, s.Properties != nil)
+	set("patternProperties", s.PatternProperties, s.PatternProperties != nil)
+	set("additionalProperties", s.AdditionalProperties, s.AdditionalProperties != nil)
+	set("propertyNames", s.PropertyNames, s.PropertyNames != nil)
+	set("maxProperties", s.MaxProperties, s.MaxProperties != nil)
+	set("minProperties", s.MinProperties, s.MinProperties != nil)
+	required, dependencies := s.marshalDependencies()
+	set("dependentRequired", required, len(required) > 0)
+	set("dependentSchemas", s.DependentSchemas, s.DependentSchemas != nil)
+	set("dependencies", dependencies, len(dependencies) > 0)
+	set("unevaluatedProperties", s.UnevaluatedProperties, s.UnevaluatedProperties != nil)
+
+	set("allOf", s.AllOf, s.AllOf != nil)
+	set("anyOf", s.AnyOf, s.AnyOf != nil)
+	set("oneOf", s.OneOf, s.OneOf != nil)
+	set("not", s.Not, s.Not != nil)
+	set("if", s.If, s.If != nil)
+	set("then", s.Then, s.Then != nil)
+	set("else", s.Else, s.Else != nil)
+
+	set("$defs", s.Defs, s.Defs != nil)
+	set("definitions", s.Definitions, s.Definitions != nil)
+	return keywords
+}
+
+// returns the types written by MarshalJSON: Types, or Type if it was changed after parsing or Types is empty.
+// An inferred Type is not written.
+func (s *Schema) declaredTypes() []string {
+	if s.Type == typeOfTypes(s.Types) || len(s.Types) > 0 && s.Type == s.parsedType {
+		return s.Types
+	}
+	if s.Type == "" || s.TypeInferred && s.Type == s.parsedType {
+		return nil
+	}
+	return []string{s.Type}
+}
+
+// splits DependentRequired into the dependentRequired keyword and the property dependencies of the dependencies
+// keyword, which also contains the schema dependencies
+func (s *Schema) marshalDependencies() (map[string][]string, map[string]interface{}) {
+	required := map[string][]string{}
+	dependencies := map[string]interface{}{}
+	for name, names := range s.DependentRequired {
+		if s.propertyDependencies[name] {
+			dependencies[name] = names
+		} else {
+			required[name] = names
+		}
+	}
+	for name, sch := range s.Dependencies {
+		dependencies[name] = sch
+	}
+	return required, dependencies
+}
+
+// encodes keywords as JSON object in canonical order, without escaping HTML characters of e.g. patterns
+func marshalKeywords(keywords map[string]interface{}) ([]byte, error) {
+	var w bytes.Buffer
+	enc := json.NewEncoder(&w)
+	enc.SetEscapeHTML(false)
+	w.WriteString("{")
+	for i, k := range orderKeywords(keywords) {
+		if i > 0 {
+			w.WriteString(",")
+		}
+		err := enc.Encode(k)
+		if err != nil {
+			return nil, err
+		}
+		w.Truncate(w.Len() - 1)
+		w.WriteString(":")
+		err = enc.Encode(keywords[k])
+		if err != nil {
+			return nil, err
+		}
+		w.Truncate(w.Len() - 1)
+	}
+	w.WriteString("}")
+	return w.Bytes(), nil
+}
+
+// returns the names of keywords in canonical order followed by unknown keywords sorted by alphabet
+func orderKeywords(keywords map[string]interface{}) []string {
+	order := make([]string, 0, len(keywords))
+	for _, k := range keywordTable {
+		if _, ok := keywords[k.name]; ok {
+			order = append(order, k.name)
+		}
+	}
+	var unknown []string
+	for k := range keywords {
+		if _, ok := knownKeywords[k]; !ok {
+			unknown = append(unknown, k)
+		}
+	}
+	sort.Strings(unknown)
+	return append(order, unknown...)
+}
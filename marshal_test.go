@@ -0,0 +1,242 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/tfkhsr/jsonschema/fixture"
+)
+
+func TestMarshalSchemaFixtures(t *testing.T) {
+	fs := map[string]string{
+		"TestSchemaWithDefinitions":                fixture.TestSchemaWithDefinitions,
+		"TestSchemaDirect":                         fixture.TestSchemaDirect,
+		"TestSchemaWithNestedDefinitions":          fixture.TestSchemaWithNestedDefinitions,
+		"TestSchemaPrimitiveTypes":                 fixture.TestSchemaPrimitiveTypes,
+		"TestSchemaRequiredValidation":             fixture.TestSchemaRequiredValidation,
+		"TestSchemaWithArrayOfObjects":             fixture.TestSchemaWithArrayOfObjects,
+		"TestSchemaEnumValidation":                 fixture.TestSchemaEnumValidation,
+		"TestSchemaCompositionValidation":          fixture.TestSchemaCompositionValidation,
+		"TestSchemaAdditionalPropertiesValidation": fixture.TestSchemaAdditionalPropertiesValidation,
+		"TestSchemaConstraintValidation":           fixture.TestSchemaConstraintValidation,
+		"TestSchemaArrayValidation":                fixture.TestSchemaArrayValidation,
+		"TestSchemaObjectValidation":               fixture.TestSchemaObjectValidation,
+		"TestSchemaConditionalValidation":          fixture.TestSchemaConditionalValidation,
+		"TestSchemaNullableValidation":             fixture.TestSchemaNullableValidation,
+		"TestSchemaExternalRef":                    fixture.TestSchemaExternalRef,
+		"TestSchemaExternalRefCommon":              fixture.TestSchemaExternalRefCommon,
+		"TestSchemaIDAndAnchor":                    fixture.TestSchemaIDAndAnchor,
+		"TestSchemaDraft202012":                    fixture.TestSchemaDraft202012,
+		"TestSchemaDraft7":                         fixture.TestSchemaDraft7,
+		"TestSchemaEscapedPointers":                fixture.TestSchemaEscapedPointers,
+		"TestSchemaRecursive":                      fixture.TestSchemaRecursive,
+		"TestSchemaTypeInference":                  fixture.TestSchemaTypeInference,
+		"TestSchemaBooleanSchemas":                 fixture.TestSchemaBooleanSchemas,
+	}
+	for k, v := range fs {
+		idx, err := Parse([]byte(v))
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := idx.MarshalSchema()
+		if err != nil {
+			t.Fatalf("fixture %s does not marshal: %s", k, err)
+		}
+
+		var declared, marshalled interface{}
+		json.Unmarshal([]byte(v), &declared)
+		err = json.Unmarshal(b, &marshalled)
+		if err != nil {
+			t.Fatalf("fixture %s marshals to invalid JSON: %s", k, err)
+		}
+		if !reflect.DeepEqual(declared, marshalled) {
+			t.Fatalf("fixture %s should marshal to its document but marshals to\n%s", k, b)
+		}
+
+		err = ValidateSchemaDocument(b)
+		if err != nil {
+			t.Fatalf("fixture %s marshals to an invalid schema document: %s", k, err)
+		}
+
+		idx, err = Parse(b)
+		if err != nil {
+			t.Fatalf("marshalled fixture %s does not parse: %s", k, err)
+		}
+		again, err := idx.MarshalSchema()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(again) != string(b) {
+			t.Fatalf("marshalled fixture %s should round-trip but marshals to\n%s\ninstead of\n%s", k, again, b)
+		}
+	}
+}
+
+func TestMarshalJSON(t *testing.T) {
+	table := []struct {
+		Schema   string
+		Expected string
+	}{
+		// keywords in canonical order, definitions last
+		{
+			`{"definitions": {"b": {}, "a": {}}, "type": "object", "properties": {"id": {"type": "string"}}, "title": "Movie", "$schema": "http://json-schema.org/draft-07/schema#"}`,
+			`{"$schema":"http://json-schema.org/draft-07/schema#","title":"Movie","type":"object","properties":{"id":{"type":"string"}},"definitions":{"a":{},"b":{}}}`,
+		},
+		// declared $ref and type instead of computed ones
+		{
+			`{"definitions": {"a": {"type": ["string", "null"]}, "b": {"$ref": "#/definitions/a"}, "c": {"properties": {}}}}`,
+			`{"definitions":{"a":{"type":["string","null"]},"b":{"$ref":"#/definitions/a"},"c":{"properties":{}}}}`,
+		},
+		// keywords without fields and unknown keywords as parsed, patterns without escaping
+		{
+			`{"x-go-type": "Movie", "format": "date", "default": "<none>", "pattern": "^<a>&$", "examples": [1, {"b": null}]}`,
+			`{"default":"<none>","examples":[1,{"b":null}],"format":"date","pattern":"^<a>&$","x-go-type":"Movie"}`,
+		},
+		// property and schema dependencies of the draft-07 dependencies keyword
+		{
+			`{"dependencies": {"b": {"required": ["c"]}, "a": ["b"]}, "dependentRequired": {"c": ["a"]}}`,
+			`{"dependentRequired":{"c":["a"]},"dependencies":{"a":["b"],"b":{"required":["c"]}}}`,
+		},
+		// unknown keywords named like computed fields
+		{
+			`{"definitions": {"a": {"name": "b", "pointer": "c", "type": "string"}}}`,
+			`{"definitions":{"a":{"type":"string","name":"b","pointer":"c"}}}`,
+		},
+		// boolean schemas, null const and list of items
+		{
+			`{"items": [true, false], "additionalItems": {"const": null}}`,
+			`{"items":[true,false],"additionalItems":{"const":null}}`,
+		},
+	}
+	for _, e := range table {
+		idx, err := Parse([]byte(e.Schema))
+		if err != nil {
+			t.Fatal(err)
+		}
+		// json.Marshal would escape HTML characters of the pattern
+		b, err := idx.schemas["#"].MarshalJSON()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != e.Expected {
+			t.Fatalf("%v should marshal to\n%v\nbut marshals to\n%s", e.Schema, e.Expected, b)
+		}
+	}
+}
+
+func TestMarshalSchemaEdited(t *testing.T) {
+	idx, err := Parse([]byte(`{"definitions": {"movie": {"type": "object", "properties": {"title": {"type": "string"}}}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	movie := idx.schemas["#/definitions/movie"]
+	movie.Required = append(movie.Required, "title")
+	minLength := 1
+	movie.Properties["title"].MinLength = &minLength
+	movie.Properties["year"] = &Schema{Type: "integer"}
+
+	b, err := idx.MarshalSchema()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{
+  "definitions": {
+    "movie": {
+      "type": "object",
+      "required": [
+        "title"
+      ],
+      "properties": {
+        "title": {
+          "type": "string",
+          "minLength": 1
+        },
+        "year": {
+          "type": "integer"
+        }
+      }
+    }
+  }
+}`
+	if string(b) != expected {
+		t.Fatalf("edited schema should marshal to\n%v\nbut marshals to\n%s", expected, b)
+	}
+
+	idx, err = Parse(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := idx.ValidateJSON("#/definitions/movie", []byte(`{"year": 1979}`)); err == nil || !strings.Contains(err.Error(), "title") {
+		t.Fatalf("edited schema should require title but validation returned %v", err)
+	}
+}
+
+func TestMarshalJSONEditedFields(t *testing.T) {
+	table := []struct {
+		Schema   string
+		Edit     func(s *Schema)
+		Expected string
+	}{
+		{`{"type": "string"}`, func(s *Schema) { s.Type = "integer" }, `{"type":"integer"}`},
+		{`{"type": ["string", "null"]}`, func(s *Schema) { s.Type = "integer" }, `{"type":"integer"}`},
+		{`{"type": "string"}`, func(s *Schema) { s.Types = []string{"integer", "null"} }, `{"type":["integer","null"]}`},
+		{`{"properties": {"id": {}}}`, func(s *Schema) { s.Type = "array" }, `{"type":"array","properties":{"id":{}}}`},
+		{`{"properties": {"id": {}}}`, func(s *Schema) {}, `{"properties":{"id":{}}}`},
+		{`{"const": "movie"}`, func(s *Schema) { s.Const = nil }, `{}`},
+		{`{"const": "movie"}`, func(s *Schema) { s.Const = "series" }, `{"const":"series"}`},
+		{`{"const": null}`, func(s *Schema) {}, `{"const":null}`},
+		{`{}`, func(s *Schema) { s.Const = "movie" }, `{"const":"movie"}`},
+		{`{"maximum": 9007199254740993}`, func(s *Schema) {}, `{"maximum":9007199254740993}`},
+		{`{"maximum": 9007199254740993}`, func(s *Schema) { m := 10.0; s.Maximum = &m }, `{"maximum":10}`},
+		{`{"enum": [9007199254740993, 1.5]}`, func(s *Schema) {}, `{"enum":[9007199254740993,1.5]}`},
+	}
+	for _, e := range table {
+		idx, err := Parse([]byte(e.Schema))
+		if err != nil {
+			t.Fatal(err)
+		}
+		s := idx.schemas["#"]
+		e.Edit(s)
+		b, err := s.MarshalJSON()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != e.Expected {
+			t.Fatalf("edited %v should marshal to %v but marshals to %s", e.Schema, e.Expected, b)
+		}
+	}
+}
+
+func TestMarshalJSONEditedCopy(t *testing.T) {
+	idx, err := Parse([]byte(`{"definitions": {"user": {"required": ["id"]}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	user, _ := idx.Get("#/definitions/user")
+	edited := *user
+	edited.Required = []string{"id", "name"}
+	b, err := edited.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `{"required":["id","name"]}` {
+		t.Fatalf("edited copy should marshal to {\"required\":[\"id\",\"name\"]} but marshals to %s", b)
+	}
+	b, err = user.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `{"required":["id"]}` {
+		t.Fatalf("schema of the index should be unchanged but marshals to %s", b)
+	}
+}
+
+func TestMarshalSchemaWithoutRoot(t *testing.T) {
+	idx := newIndex()
+	if _, err := idx.MarshalSchema(); err == nil {
+		t.Fatalf("index without root should not marshal")
+	}
+}
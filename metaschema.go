@@ -0,0 +1,126 @@
+package jsonschema
+
+import (
+	"embed"
+	"fmt"
+	"net/url"
+	"path"
+	"sync"
+)
+
+// meta-schema documents of all drafts at the path of their URI on json-schema.org
+//
+//go:embed metaschemas
+var metaSchemaFS embed.FS
+
+// URIs of the meta-schemas by draft
+var metaSchemaURIs = map[Draft]string{
+	Draft7:      "http://json-schema.org/draft-07/schema",
+	Draft201909: "https://json-schema.org/draft/2019-09/schema",
+	Draft202012: "https://json-schema.org/draft/2020-12/schema",
+}
+
+// loads the embedded meta-schema documents by their URI
+type metaSchemaLoader struct{}
+
+func (metaSchemaLoader) Load(uri string) ([]byte, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, err
+	}
+	if u.Host != "json-schema.org" {
+		return nil, fmt.Errorf("jsonschema: %v is not an embedded meta-schema", uri)
+	}
+	return metaSchemaFS.ReadFile(path.Join("metaschemas", u.Path) + ".json")
+}
+
+// meta-schemas parsed once on first use
+var metaSchemas struct {
+	once sync.Once
+	idx  map[Draft]*Index
+	err  error
+}
+
+// returns the index of the meta-schema of a draft, its root is indexed by #
+func metaSchema(d Draft) (*Index, error) {
+	metaSchemas.once.Do(func() {
+		metaSchemas.idx = map[Draft]*Index{}
+		for draft, uri := range metaSchemaURIs {
+			idx, err := ParseWithLoader(uri, metaSchemaLoader{})
+			if err != nil {
+				metaSchemas.err = err
+				return
+			}
+			metaSchemas.idx[draft] = idx
+		}
+	})
+	if metaSchemas.err != nil {
+		return nil, metaSchemas.err
+	}
+	idx, ok := metaSchemas.idx[d]
+	if !ok {
+		return nil, fmt.Errorf("jsonschema: no meta-schema for draft %v", d)
+	}
+	return idx, nil
+}
+
+// ValidateSchemaDocument checks a raw schema document against the meta-schema of the draft selected by its $schema,
+// reporting all violations as ValidationErrors.
+//
+// The meta-schemas of draft-07, 2019-09 and 2020-12 are embedded. A document without $schema, which Parse reads
+// with the keywords of all drafts, has to be valid against any of them, otherwise the violations of the 2020-12
+// meta-schema are reported.
+func ValidateSchemaDocument(b []byte) error {
+	doc, err := decodeInstance(b)
+	if err != nil {
+		return err
+	}
+
+	drafts := []Draft{Draft202012, Draft201909, Draft7}
+	if object, ok := doc.(map[string]interface{}); ok {
+		if uri, ok := object["$schema"].(string); ok {
+			d := draftOf(uri)
+			if d == DraftAny {
+				return fmt.Errorf("jsonschema: unknown meta-schema %v", uri)
+			}
+			drafts = []Draft{d}
+		}
+	}
+
+	var errs error
+	for _, d := range drafts {
+		idx, err := metaSchema(d)
+		if err != nil {
+			return err
+		}
+		root, _ := idx.Get("#")
+		err = root.ValidateWithOptions(idx, doc, ValidateOptions{AllErrors: true})
+		if err == nil {
+			return nil
+		}
+		violations, ok := err.(ValidationErrors)
+		if !ok {
+			return err
+		}
+		if errs == nil {
+			errs = uniqueViolations(violations)
+		}
+	}
+	return errs
+}
+
+// returns violations without repetitions of a message at the same instance pointer,
+// e.g. the type of a schema checked by each vocabulary of a meta-schema
+func uniqueViolations(errs ValidationErrors) ValidationErrors {
+	type violation struct{ ptr, msg string }
+	seen := map[violation]bool{}
+	var unique ValidationErrors
+	for _, err := range errs {
+		v := violation{err.InstancePointer, err.Message}
+		if !seen[v] {
+			seen[v] = true
+			unique = append(unique, err)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,119 @@
+package jsonschema
+
+import (
+	"io/fs"
+	"strings"
+	"testing"
+
+	"github.com/tfkhsr/jsonschema/fixture"
+)
+
+func TestValidateSchemaDocumentFixtures(t *testing.T) {
+	fs := map[string]string{
+		"TestSchemaWithDefinitions":                fixture.TestSchemaWithDefinitions,
+		"TestSchemaDirect":                         fixture.TestSchemaDirect,
+		"TestSchemaWithNestedDefinitions":          fixture.TestSchemaWithNestedDefinitions,
+		"TestSchemaPrimitiveTypes":                 fixture.TestSchemaPrimitiveTypes,
+		"TestSchemaRequiredValidation":             fixture.TestSchemaRequiredValidation,
+		"TestSchemaWithArrayOfObjects":             fixture.TestSchemaWithArrayOfObjects,
+		"TestSchemaEnumValidation":                 fixture.TestSchemaEnumValidation,
+		"TestSchemaCompositionValidation":          fixture.TestSchemaCompositionValidation,
+		"TestSchemaAdditionalPropertiesValidation": fixture.TestSchemaAdditionalPropertiesValidation,
+		"TestSchemaConstraintValidation":           fixture.TestSchemaConstraintValidation,
+		"TestSchemaArrayValidation":                fixture.TestSchemaArrayValidation,
+		"TestSchemaObjectValidation":               fixture.TestSchemaObjectValidation,
+		"TestSchemaConditionalValidation":          fixture.TestSchemaConditionalValidation,
+		"TestSchemaNullableValidation":             fixture.TestSchemaNullableValidation,
+		"TestSchemaExternalRef":                    fixture.TestSchemaExternalRef,
+		"TestSchemaExternalRefCommon":              fixture.TestSchemaExternalRefCommon,
+		"TestSchemaIDAndAnchor":                    fixture.TestSchemaIDAndAnchor,
+		"TestSchemaDraft202012":                    fixture.TestSchemaDraft202012,
+		"TestSchemaDraft7":                         fixture.TestSchemaDraft7,
+		"TestSchemaEscapedPointers":                fixture.TestSchemaEscapedPointers,
+		"TestSchemaRecursive":                      fixture.TestSchemaRecursive,
+		"TestSchemaTypeInference":                  fixture.TestSchemaTypeInference,
+		"TestSchemaBooleanSchemas":                 fixture.TestSchemaBooleanSchemas,
+	}
+	for k, v := range fs {
+		err := ValidateSchemaDocument([]byte(v))
+		if err != nil {
+			t.Errorf("fixture %s is invalid: %s", k, err)
+		}
+	}
+}
+
+func TestValidateSchemaDocumentMetaSchemas(t *testing.T) {
+	err := fs.WalkDir(metaSchemaFS, "metaschemas", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		b, err := metaSchemaFS.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		err = ValidateSchemaDocument(b)
+		if err != nil {
+			t.Errorf("meta-schema %s is invalid: %s", path, err)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestValidateSchemaDocument(t *testing.T) {
+	table := []struct {
+		Schema string
+		Error  string
+	}{
+		{`{"type": "object", "properties": {"id": {"type": "string"}}}`, ""},
+		{`true`, ""},
+		{`{"type": 1}`, "jsonschema: invalid instance at #/type: must match at least one schema of anyOf"},
+		{`{"properties": {"id": {"type": "text"}}}`, "jsonschema: invalid instance at #/properties/id/type: must match at least one schema of anyOf"},
+		{`{"definitions": {"movie": {"required": "id"}}}`, "jsonschema: invalid instance at #/definitions/movie/required: expected array but got string"},
+		{`{"$schema": "https://json-schema.org/draft/2020-12/schema", "$defs": {"a": {"minLength": -1}}}`, "jsonschema: invalid instance at #/$defs/a/minLength: must be >= 0"},
+		{`{"$schema": "https://json-schema.org/draft/2020-12/schema", "items": [{"type": "string"}]}`, "jsonschema: invalid instance at #/items: expected object or boolean but got array"},
+		{`{"$schema": "http://json-schema.org/draft-07/schema#", "items": [{"type": "string"}]}`, ""},
+		{`{"$schema": "https://json-schema.org/draft/2019-09/schema", "properties": {"a": {"items": {"maxItems": "1"}}}}`, "jsonschema: invalid instance at #/properties/a/items: must match at least one schema of anyOf"},
+		{`{"$schema": "https://json-schema.org/draft/2020-12/schema", "properties": {"a": {"items": {"maxItems": "1"}}}}`, "jsonschema: invalid instance at #/properties/a/items/maxItems: expected integer but got string"},
+		{`{"$schema": "http://example.com/schema"}`, "jsonschema: unknown meta-schema http://example.com/schema"},
+		{`{"items": [{"type": "string"}]}`, ""},
+		{`1`, "jsonschema: invalid instance at #: expected object or boolean but got number"},
+		{`{"type": "object",}`, "jsonschema: invalid character '}' looking for beginning of object key string"},
+	}
+	for _, r := range table {
+		err := ValidateSchemaDocument([]byte(r.Schema))
+		msg := ""
+		if err != nil {
+			msg = err.Error()
+		}
+		if msg != r.Error {
+			t.Errorf("%s: expected error %q but got %q", r.Schema, r.Error, msg)
+		}
+	}
+}
+
+func TestParseValidateDocument(t *testing.T) {
+	_, err := ParseWithOptions([]byte(`{"properties": {"id": {"type": "string", "minLength": "1"}}}`), ParseOptions{})
+	if err == nil || !strings.HasPrefix(err.Error(), "jsonschema: 1:55: json: cannot unmarshal") {
+		t.Fatalf("expected unmarshal error but got %v", err)
+	}
+
+	_, err = ParseWithOptions([]byte(`{"properties": {"id": {"type": "string", "minLength": "1"}}}`), ParseOptions{ValidateDocument: true})
+	if _, ok := err.(ValidationErrors); !ok {
+		t.Fatalf("expected ValidationErrors but got %v", err)
+	}
+	if err.Error() != "jsonschema: invalid instance at #/properties/id/minLength: expected integer but got string" {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	loader := MapLoader{
+		"schema.json": []byte(`{"properties": {"address": {"$ref": "common.json#/definitions/address"}}}`),
+		"common.json": []byte(`{"definitions": {"address": {"required": [1]}}}`),
+	}
+	_, err = ParseWithLoaderOptions("schema.json", loader, ParseOptions{ValidateDocument: true})
+	if err == nil || err.Error() != "jsonschema: invalid instance at #/definitions/address/required/0: expected string but got number" {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
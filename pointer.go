@@ -0,0 +1,93 @@
+package jsonschema
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Pointer is a JSON pointer (RFC 6901) in its URI fragment representation, e.g. #/definitions/a~1b,
+// optionally preceded by the URI of its document, e.g. common.json#/definitions/address.
+//
+// Tokens are escaped with ~0 for ~ and ~1 for /, characters not allowed in a URI fragment are percent-encoded.
+// Index keys and Schema.Pointer hold the string of a Pointer.
+type Pointer string
+
+// Append returns the pointer to the unescaped token below p
+func (p Pointer) Append(token string) Pointer {
+	return p + "/" + Pointer(escapeFragment(EscapeToken(token)))
+}
+
+// Parent returns the pointer to the parent of p, the document root is its own parent
+func (p Pointer) Parent() Pointer {
+	i := strings.LastIndex(string(p), "/")
+	if i < 0 || i < strings.Index(string(p), "#") {
+		return p
+	}
+	return p[:i]
+}
+
+// URI returns the URI of the document of p, "" for the root document
+func (p Pointer) URI() string {
+	uri, _, _ := strings.Cut(string(p), "#")
+	return uri
+}
+
+// Tokens returns the unescaped tokens of p, none for the document root
+func (p Pointer) Tokens() []string {
+	_, fragment, _ := strings.Cut(string(p), "#")
+	if fragment == "" {
+		return nil
+	}
+	if f, err := url.PathUnescape(fragment); err == nil {
+		fragment = f
+	}
+	tokens := strings.Split(strings.TrimPrefix(fragment, "/"), "/")
+	for i, t := range tokens {
+		tokens[i] = unescapeToken(t)
+	}
+	return tokens
+}
+
+// pointerFromFragment returns the pointer to a decoded JSON pointer fragment below the document uri,
+// e.g. the fragment of a $ref
+func pointerFromFragment(uri string, fragment string) Pointer {
+	return Pointer(uri + "#" + escapeFragment(fragment))
+}
+
+var tokenEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// EscapeToken escapes ~ and / of a reference token as ~0 and ~1 as defined in https://tools.ietf.org/html/rfc6901#section-3
+func EscapeToken(token string) string {
+	return tokenEscaper.Replace(token)
+}
+
+var tokenUnescaper = strings.NewReplacer("~1", "/", "~0", "~")
+
+// reverts escapeToken, ~01 unescapes to ~1
+func unescapeToken(token string) string {
+	return tokenUnescaper.Replace(token)
+}
+
+// percent-encodes all characters not allowed in a URI fragment (RFC 3986)
+func escapeFragment(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if isFragmentChar(c) {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+	return b.String()
+}
+
+// returns true for unreserved characters, sub-delims, :, @, / and ?
+func isFragmentChar(c byte) bool {
+	switch {
+	case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+		return true
+	}
+	return strings.IndexByte("-._~!$&'()*+,;=:@/?", c) >= 0
+}
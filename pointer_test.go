@@ -0,0 +1,64 @@
+package jsonschema
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPointer(t *testing.T) {
+	table := []struct {
+		Pointer Pointer
+		Tokens  []string
+		Parent  Pointer
+		URI     string
+	}{
+		{"#", nil, "#", ""},
+		{"#/definitions/movie", []string{"definitions", "movie"}, "#/definitions", ""},
+		{"#/definitions/a~1b", []string{"definitions", "a/b"}, "#/definitions", ""},
+		{"#/definitions/x~0y", []string{"definitions", "x~y"}, "#/definitions", ""},
+		{"#/definitions/~01", []string{"definitions", "~1"}, "#/definitions", ""},
+		{"#/properties/display%20name", []string{"properties", "display name"}, "#/properties", ""},
+		{"#/patternProperties/%5E%5Ba-z%5D+$", []string{"patternProperties", "^[a-z]+$"}, "#/patternProperties", ""},
+		{"schemas/common.json#", nil, "schemas/common.json#", "schemas/common.json"},
+		{"schemas/common.json#/definitions/city", []string{"definitions", "city"}, "schemas/common.json#/definitions", "schemas/common.json"},
+	}
+	for _, ts := range table {
+		if tokens := ts.Pointer.Tokens(); !reflect.DeepEqual(tokens, ts.Tokens) {
+			t.Fatalf("tokens of %v should be %q but are %q", ts.Pointer, ts.Tokens, tokens)
+		}
+		if p := ts.Pointer.Parent(); p != ts.Parent {
+			t.Fatalf("parent of %v should be %v but is %v", ts.Pointer, ts.Parent, p)
+		}
+		if uri := ts.Pointer.URI(); uri != ts.URI {
+			t.Fatalf("uri of %v should be %v but is %v", ts.Pointer, ts.URI, uri)
+		}
+	}
+}
+
+func TestPointerAppend(t *testing.T) {
+	table := []struct {
+		Pointer Pointer
+		Token   string
+		Result  Pointer
+	}{
+		{"#", "definitions", "#/definitions"},
+		{"#/definitions", "a/b", "#/definitions/a~1b"},
+		{"#/definitions", "x~y", "#/definitions/x~0y"},
+		{"#/definitions", "~1", "#/definitions/~01"},
+		{"#/properties", "display name", "#/properties/display%20name"},
+		{"#/properties", "100%", "#/properties/100%25"},
+		{"#/properties", "größe", "#/properties/gr%C3%B6%C3%9Fe"},
+		{"#/patternProperties", "^[a-z]+$", "#/patternProperties/%5E%5Ba-z%5D+$"},
+		{"common.json#", "$defs", "common.json#/$defs"},
+	}
+	for _, ts := range table {
+		p := ts.Pointer.Append(ts.Token)
+		if p != ts.Result {
+			t.Fatalf("%v appended with %v should be %v but is %v", ts.Pointer, ts.Token, ts.Result, p)
+		}
+		tokens := p.Tokens()
+		if tokens[len(tokens)-1] != ts.Token {
+			t.Fatalf("last token of %v should be %v but is %v", p, ts.Token, tokens[len(tokens)-1])
+		}
+	}
+}
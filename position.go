@@ -0,0 +1,191 @@
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Position is the location of a schema in its document
+type Position struct {
+	// File of the document, the URI of documents loaded by a Loader, see ParseOptions for Parse
+	File string
+
+	// Byte offset of the start of the schema, starting at 0
+	Offset int
+
+	// Line of the start of the schema, starting at 1
+	Line int
+
+	// Column of the start of the schema in bytes, starting at 1
+	Column int
+}
+
+// IsValid returns true if the position is known
+func (p Position) IsValid() bool {
+	return p.Line > 0
+}
+
+// String returns the position as file:line:col, line:col without file, or - if it is unknown
+func (p Position) String() string {
+	s := p.File
+	if p.IsValid() {
+		if s != "" {
+			s += ":"
+		}
+		s += fmt.Sprintf("%v:%v", p.Line, p.Column)
+	}
+	if s == "" {
+		s = "-"
+	}
+	return s
+}
+
+// Errorf returns an error located at the position of the schema, e.g. jsonschema: schema.json:3:5: message
+func (s *Schema) Errorf(format string, args ...interface{}) error {
+	if !s.Position.IsValid() {
+		return fmt.Errorf("jsonschema: "+format, args...)
+	}
+	return fmt.Errorf("jsonschema: %v: %v", s.Position, fmt.Sprintf(format, args...))
+}
+
+// maps byte offsets of a document to lines and columns
+type lineIndex struct {
+	file string
+
+	// offsets of the first byte of each line
+	lines []int
+}
+
+func newLineIndex(file string, b []byte) *lineIndex {
+	l := &lineIndex{file: file, lines: []int{0}}
+	for i, c := range b {
+		if c == '\n' {
+			l.lines = append(l.lines, i+1)
+		}
+	}
+	return l
+}
+
+// returns the position of a byte offset
+func (l *lineIndex) position(offset int) Position {
+	line := sort.Search(len(l.lines), func(i int) bool { return l.lines[i] > offset })
+	return Position{File: l.file, Offset: offset, Line: line, Column: offset - l.lines[line-1] + 1}
+}
+
+// returns the positions of all values of a document by their pointer below root,
+// decoding the document token by token
+func scanPositions(b []byte, file string, root Pointer) (map[Pointer]Position, error) {
+	lines := newLineIndex(file, b)
+	positions := map[Pointer]Position{}
+	d := json.NewDecoder(bytes.NewReader(b))
+
+	var scan func(ptr Pointer) error
+	scan = func(ptr Pointer) error {
+		// the offset of a decoder is at the end of the previous token, before any separator
+		start := int(d.InputOffset())
+		for start < len(b) && bytes.IndexByte([]byte(" \t\r\n,:"), b[start]) >= 0 {
+			start++
+		}
+		positions[ptr] = lines.position(start)
+
+		t, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch t {
+		case json.Delim('{'):
+			for d.More() {
+				key, err := d.Token()
+				if err != nil {
+					return err
+				}
+				name, _ := key.(string)
+				err = scan(ptr.Append(name))
+				if err != nil {
+					return err
+				}
+			}
+			_, err = d.Token()
+		case json.Delim('['):
+			for i := 0; d.More(); i++ {
+				err := scan(ptr.Append(strconv.Itoa(i)))
+				if err != nil {
+					return err
+				}
+			}
+			_, err = d.Token()
+		}
+		return err
+	}
+
+	err := scan(root)
+	if err != nil {
+		return nil, err
+	}
+	return positions, nil
+}
+
+// returns an error decoding a document, locating syntax errors and values of the wrong type
+func decodeError(b []byte, file string, err error) error {
+	switch e := err.(type) {
+	case *json.SyntaxError:
+		// the offset of a syntax error is after the invalid byte
+		return fmt.Errorf("jsonschema: %v: %v", newLineIndex(file, b).position(max(int(e.Offset)-1, 0)), err)
+	case *json.UnmarshalTypeError:
+		if p, ok := typeErrorPosition(b, file, e); ok {
+			return fmt.Errorf("jsonschema: %v: %v", p, err)
+		}
+	}
+	if err == errNullSchema {
+		offset := len(b) - len(bytes.TrimLeft(b, " \t\r\n"))
+		return fmt.Errorf("jsonschema: %v: %v", newLineIndex(file, b).position(offset), strings.TrimPrefix(err.Error(), "jsonschema: "))
+	}
+	// errors of Schema.UnmarshalJSON are already prefixed
+	msg := strings.TrimPrefix(err.Error(), "jsonschema: ")
+	if file != "" {
+		return fmt.Errorf("jsonschema: %v: %v", file, msg)
+	}
+	return fmt.Errorf("jsonschema: %v", msg)
+}
+
+// returns the position of the value failing to unmarshal with a type error. The offset of the error is at the end
+// of the value relative to the schema containing it, which is found among the values at the path of the field.
+func typeErrorPosition(b []byte, file string, e *json.UnmarshalTypeError) (Position, bool) {
+	if e.Field == "" {
+		return Position{}, false
+	}
+	positions, err := scanPositions(b, file, "#")
+	if err != nil {
+		return Position{}, false
+	}
+	var suffix Pointer
+	for _, t := range strings.Split(e.Field, ".") {
+		suffix = suffix.Append(t)
+	}
+
+	ptrs := make([]string, 0, len(positions))
+	for ptr := range positions {
+		ptrs = append(ptrs, string(ptr))
+	}
+	sort.Strings(ptrs)
+	for _, ptr := range ptrs {
+		parent, ok := strings.CutSuffix(ptr, string(suffix))
+		if !ok {
+			continue
+		}
+		schema, ok := positions[Pointer(parent)]
+		if !ok {
+			continue
+		}
+		p := positions[Pointer(ptr)]
+		end := schema.Offset + int(e.Offset)
+		if p.Offset < end && end <= len(b) && json.Valid(b[p.Offset:end]) {
+			return p, true
+		}
+	}
+	return Position{}, false
+}
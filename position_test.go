@@ -0,0 +1,164 @@
+package jsonschema
+
+import (
+	"testing"
+)
+
+func TestSchemaPositions(t *testing.T) {
+	schema := `{
+  "definitions": {
+    "movie": {
+      "type": "object",
+      "properties": {
+        "a/b": { "type": "string" },
+        "tags": {"type": "array", "items": [true, {"type": "string"}]}
+      }
+    }
+  }
+}`
+	idx, err := ParseWithOptions([]byte(schema), ParseOptions{File: "schema.json"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	table := []struct {
+		Pointer  string
+		Position string
+		Offset   int
+	}{
+		{"#", "schema.json:1:1", 0},
+		{"#/definitions/movie", "schema.json:3:14", 34},
+		{"#/definitions/movie/properties/a~1b", "schema.json:6:16", 97},
+		{"#/definitions/movie/properties/tags", "schema.json:7:17", 135},
+		{"#/definitions/movie/properties/tags/items/0", "schema.json:7:45", 163},
+		{"#/definitions/movie/properties/tags/items/1", "schema.json:7:51", 169},
+	}
+	for _, ts := range table {
+		s, ok := idx.Get(ts.Pointer)
+		if !ok {
+			t.Fatalf("%v should exist in index", ts.Pointer)
+		}
+		if s.Position.String() != ts.Position || s.Position.Offset != ts.Offset {
+			t.Fatalf("%v should be at %v (offset %v) but is at %v (offset %v)", ts.Pointer, ts.Position, ts.Offset, s.Position, s.Position.Offset)
+		}
+	}
+}
+
+func TestSchemaPositionsWithLoader(t *testing.T) {
+	loader := MapLoader{
+		"schema.json": []byte(`{"properties": {"address": {"$ref": "common.json#/definitions/address"}}}`),
+		"common.json": []byte("{\n\t\"definitions\": {\n\t\t\"address\": {\"type\": \"object\"}\n\t}\n}"),
+	}
+	idx, err := ParseWithLoader("schema.json", loader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p := idx.schemas["#/properties/address"].Position.String(); p != "schema.json:1:28" {
+		t.Fatalf("#/properties/address should be at schema.json:1:28 but is at %v", p)
+	}
+	if p := idx.schemas["common.json#/definitions/address"].Position.String(); p != "common.json:3:14" {
+		t.Fatalf("common.json#/definitions/address should be at common.json:3:14 but is at %v", p)
+	}
+}
+
+func TestPositionString(t *testing.T) {
+	table := []struct {
+		Position Position
+		String   string
+	}{
+		{Position{File: "schema.json", Line: 3, Column: 5}, "schema.json:3:5"},
+		{Position{Line: 3, Column: 5}, "3:5"},
+		{Position{File: "schema.json"}, "schema.json"},
+		{Position{}, "-"},
+	}
+	for _, ts := range table {
+		if s := ts.Position.String(); s != ts.String {
+			t.Fatalf("%#v should be %v but is %v", ts.Position, ts.String, s)
+		}
+	}
+}
+
+func TestParseErrorPositions(t *testing.T) {
+	table := []struct {
+		Schema string
+		Opts   ParseOptions
+		Error  string
+	}{
+		{
+			"{\n  \"type\": \"object\",\n}",
+			ParseOptions{File: "schema.json"},
+			"jsonschema: schema.json:3:1: invalid character '}' looking for beginning of object key string",
+		},
+		{
+			"{\n  \"type\": \"object\",\n}",
+			ParseOptions{},
+			"jsonschema: 3:1: invalid character '}' looking for beginning of object key string",
+		},
+		{
+			"{\n  \"properties\": {\n    \"id\": {\"tpye\": \"string\"}\n  }\n}",
+			ParseOptions{Strict: true, File: "schema.json"},
+			"jsonschema: schema.json:3:11: unknown keyword tpye at #/properties/id/tpye, did you mean type?",
+		},
+		{
+			"{\n  \"definitions\": {\n    \"movie\": {\"required\": [\"id\"]},\n    \"actor\": {\"required\": \"id\"}\n  }\n}",
+			ParseOptions{File: "schema.json"},
+			"jsonschema: schema.json:4:27: json: cannot unmarshal string into Go struct field .required of type []string",
+		},
+		{
+			"{\"properties\": {\"id\": {\"items\": [{\"minimum\": \"1\"}]}}}",
+			ParseOptions{},
+			"jsonschema: 1:46: json: cannot unmarshal string into Go struct field .minimum of type float64",
+		},
+		{
+			"{\"type\": 1}",
+			ParseOptions{File: "schema.json"},
+			"jsonschema: schema.json: type must be a string or a list of strings: json: cannot unmarshal number into Go value of type []string",
+		},
+		{
+			"\n  null",
+			ParseOptions{File: "schema.json"},
+			"jsonschema: schema.json:2:3: schema must be an object or a boolean, not null",
+		},
+		{
+			"{\"properties\": {\"a\": null}}",
+			ParseOptions{},
+			"jsonschema: 1:22: schema at #/properties/a must be an object or a boolean, not null",
+		},
+		{
+			"{\"allOf\": [{}, null]}",
+			ParseOptions{},
+			"jsonschema: 1:16: schema at #/allOf/1 must be an object or a boolean, not null",
+		},
+		{
+			"{\"definitions\": {\"a\": null}}",
+			ParseOptions{},
+			"jsonschema: 1:23: schema at #/definitions/a must be an object or a boolean, not null",
+		},
+		{
+			"{\"items\": [null]}",
+			ParseOptions{},
+			"jsonschema: 1:12: schema at #/items/0 must be an object or a boolean, not null",
+		},
+		{
+			"{\"items\": null}",
+			ParseOptions{},
+			"jsonschema: 1:11: schema at #/items must be an object or a boolean, not null",
+		},
+		{
+			"{\"properties\": {\"a\": {\"not\": null}}}",
+			ParseOptions{},
+			"jsonschema: 1:30: schema at #/properties/a/not must be an object or a boolean, not null",
+		},
+		{
+			"{\"dependencies\": {\"a\": null}}",
+			ParseOptions{},
+			"jsonschema: 1:24: schema at #/dependencies/a must be an object or a boolean, not null",
+		},
+	}
+	for _, ts := range table {
+		_, err := ParseWithOptions([]byte(ts.Schema), ts.Opts)
+		if err == nil || err.Error() != ts.Error {
+			t.Fatalf("%v should fail with '%v' but failed with '%v'", ts.Schema, ts.Error, err)
+		}
+	}
+}
@@ -0,0 +1,1021 @@
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// Validate checks an instance against the schema.
+// References are resolved through idx.
+// The first violation is reported as *ValidationError.
+// The instance is expected in the form produced by encoding/json when decoding into an interface{}.
+func (s *Schema) Validate(idx *Index, instance interface{}) error {
+	return s.ValidateWithOptions(idx, instance, ValidateOptions{})
+}
+
+// ValidateWithOptions checks an instance against the schema using the given options
+func (s *Schema) ValidateWithOptions(idx *Index, instance interface{}, opts ValidateOptions) error {
+	v := &validator{idx: idx, opts: opts, refs: map[refVisit]bool{}}
+	err := v.validate(s, instance, "")
+	if err != nil {
+		return err
+	}
+	if len(v.errs) > 0 {
+		return v.errs
+	}
+	return nil
+}
+
+// ValidateJSON checks a raw JSON document against the schema at pointer
+func (idx *Index) ValidateJSON(pointer string, doc []byte) error {
+	return idx.ValidateJSONWithOptions(pointer, doc, ValidateOptions{})
+}
+
+// ValidateJSONWithOptions checks a raw JSON document against the schema at pointer using the given options
+func (idx *Index) ValidateJSONWithOptions(pointer string, doc []byte, opts ValidateOptions) error {
+	s, ok := idx.Get(pointer)
+	if !ok {
+		return fmt.Errorf("jsonschema: %v does not exist in index", pointer)
+	}
+	instance, err := decodeInstance(doc)
+	if err != nil {
+		return err
+	}
+	return s.ValidateWithOptions(idx, instance, opts)
+}
+
+// ValidateOptions control the validation of instances
+type ValidateOptions struct {
+	// Report all violations as ValidationErrors instead of returning the first *ValidationError
+	AllErrors bool
+}
+
+// validator walks a schema and an instance in parallel
+type validator struct {
+	idx  *Index
+	opts ValidateOptions
+
+	// collected violations if all errors are reported
+	errs ValidationErrors
+
+	// schema resources entered by the validation, outermost first, in which $dynamicRef and $recursiveRef resolve
+	scope []*Schema
+
+	// references in progress at instance pointers, a reference entered again at the same pointer is cyclic
+	refs map[refVisit]bool
+}
+
+// a schema with $ref, $dynamicRef or $recursiveRef validating the instance at an instance pointer
+type refVisit struct {
+	schema *Schema
+	ptr    string
+}
+
+// records a violation, returns it if validation should stop
+func (v *validator) fail(err *ValidationError) error {
+	if v.opts.AllErrors {
+		v.errs = append(v.errs, err)
+		return nil
+	}
+	return err
+}
+
+// validates an instance located at the instance pointer ptr against a schema
+func (v *validator) validate(s *Schema, instance interface{}, ptr string) error {
+	if kw := refKeyword(s); kw != "" {
+		r := refVisit{s, ptr}
+		if v.refs[r] {
+			return v.fail(newValidationError(s, kw, ptr, "cyclic %v at %v", kw, s.Pointer))
+		}
+		v.refs[r] = true
+		defer delete(v.refs, r)
+	}
+
+	s, err := v.resolve(s)
+	if err != nil {
+		return err
+	}
+	if r := s.resource; r != nil && (len(v.scope) == 0 || v.scope[len(v.scope)-1] != r) {
+		v.scope = append(v.scope, r)
+		defer func() { v.scope = v.scope[:len(v.scope)-1] }()
+	}
+
+	if s.Boolean != nil {
+		if !*s.Boolean {
+			return v.fail(newValidationError(s, "false", ptr, "no value allowed"))
+		}
+		return nil
+	}
+
+	if types := typesOf(s); len(types) > 0 && !isOfAnyType(instance, types) {
+		err := v.fail(newValidationError(s, "type", ptr, "expected %v but got %v", strings.Join(types, " or "), typeOf(instance)))
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(s.Enum) > 0 && !containsInstance(s.Enum, instance) {
+		err := v.fail(newValidationError(s, "enum", ptr, "must be one of %v", encodeInstance(s.Enum)))
+		if err != nil {
+			return err
+		}
+	}
+
+	if s.HasConst() && !equalInstances(s.Const, instance) {
+		err := v.fail(newValidationError(s, "const", ptr, "must be %v", encodeInstance(s.Const)))
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, sch := range s.AllOf {
+		err := v.validate(sch, instance, ptr)
+		if err != nil {
+			return err
+		}
+	}
+
+	if s.IsRef() {
+		sch, err := v.refTarget(s)
+		if err != nil {
+			return err
+		}
+		err = v.validate(sch, instance, ptr)
+		if err != nil {
+			return err
+		}
+	}
+
+	if s.dynamicRefKey != "" || s.recursiveRefKey != "" {
+		sch, err := v.dynamicTarget(s)
+		if err != nil {
+			return err
+		}
+		err = v.validate(sch, instance, ptr)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(s.AnyOf) > 0 {
+		n, err := v.countMatches(s.AnyOf, instance, ptr)
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			err := v.fail(newValidationError(s, "anyOf", ptr, "must match at least one schema of anyOf"))
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(s.OneOf) > 0 {
+		n, err := v.countMatches(s.OneOf, instance, ptr)
+		if err != nil {
+			return err
+		}
+		if n != 1 {
+			err := v.fail(newValidationError(s, "oneOf", ptr, "must match exactly one schema of oneOf but matches %v", n))
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if s.Not != nil {
+		ok, err := v.matches(s.Not, instance, ptr)
+		if err != nil {
+			return err
+		}
+		if ok {
+			err := v.fail(newValidationError(s, "not", ptr, "must not match schema of not"))
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if s.If != nil {
+		err := v.validateConditional(s, instance, ptr)
+		if err != nil {
+			return err
+		}
+	}
+
+	if f, ok := numberOf(instance); ok {
+		err := v.validateNumber(s, f, ptr)
+		if err != nil {
+			return err
+		}
+	}
+
+	if str, ok := instance.(string); ok {
+		err := v.validateString(s, str, ptr)
+		if err != nil {
+			return err
+		}
+	}
+
+	switch i := instance.(type) {
+	case map[string]interface{}:
+		for _, name := range s.Required {
+			if _, ok := i[name]; !ok {
+				err := v.fail(newValidationError(s, "required", ptr+"/"+EscapeToken(name), "missing property %v", name))
+				if err != nil {
+					return err
+				}
+			}
+		}
+		err := v.validateObject(s, i, ptr)
+		if err != nil {
+			return err
+		}
+		for _, name := range sortedPropertyNames(i) {
+			err := v.validateProperty(s, name, i[name], ptr+"/"+EscapeToken(name))
+			if err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		err := v.validateArray(s, i, ptr)
+		if err != nil {
+			return err
+		}
+	}
+
+	return v.validateUnevaluated(s, instance, ptr)
+}
+
+// validates the properties and items not evaluated by any other keyword against
+// unevaluatedProperties and unevaluatedItems
+func (v *validator) validateUnevaluated(s *Schema, instance interface{}, ptr string) error {
+	switch i := instance.(type) {
+	case map[string]interface{}:
+		_, u := s.unevaluated()
+		if u == nil {
+			return nil
+		}
+		evaluated, err := v.evaluatedProperties(s, i, ptr, false)
+		if err != nil {
+			return err
+		}
+		for _, name := range sortedPropertyNames(i) {
+			if evaluated[name] {
+				continue
+			}
+			if u.Boolean != nil && !*u.Boolean {
+				err = v.fail(newValidationError(s, "unevaluatedProperties", ptr+"/"+EscapeToken(name), "unevaluated property %v is not allowed", name))
+			} else {
+				err = v.validate(u, i[name], ptr+"/"+EscapeToken(name))
+			}
+			if err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		u, _ := s.unevaluated()
+		if u == nil {
+			return nil
+		}
+		evaluated, err := v.evaluatedItems(s, i, ptr, false)
+		if err != nil {
+			return err
+		}
+		for n, item := range i {
+			if evaluated[n] {
+				continue
+			}
+			if u.Boolean != nil && !*u.Boolean {
+				err = v.fail(newValidationError(s, "unevaluatedItems", ptr+"/"+strconv.Itoa(n), "unevaluated item %v is not allowed", n))
+			} else {
+				err = v.validate(u, item, ptr+"/"+strconv.Itoa(n))
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// returns the names of the properties of an object evaluated by a schema and its valid subschemas,
+// including the properties evaluated by its own unevaluatedProperties if unevaluated is set
+func (v *validator) evaluatedProperties(s *Schema, object map[string]interface{}, ptr string, unevaluated bool) (map[string]bool, error) {
+	s, err := v.resolve(s)
+	if err != nil {
+		return nil, err
+	}
+	_, u := s.unevaluated()
+	evaluated := map[string]bool{}
+	for name := range object {
+		if _, ok := s.Properties[name]; ok || s.AdditionalProperties != nil || unevaluated && u != nil {
+			evaluated[name] = true
+			continue
+		}
+		for pattern := range s.PatternProperties {
+			re, err := compilePattern(pattern)
+			if err != nil {
+				return nil, err
+			}
+			if re.MatchString(name) {
+				evaluated[name] = true
+			}
+		}
+	}
+
+	subschemas, err := v.applicableSubschemas(s, object, ptr)
+	if err != nil {
+		return nil, err
+	}
+	for _, sch := range subschemas {
+		names, err := v.evaluatedProperties(sch, object, ptr, true)
+		if err != nil {
+			return nil, err
+		}
+		for name := range names {
+			evaluated[name] = true
+		}
+	}
+	return evaluated, nil
+}
+
+// returns the indices of the items of an array evaluated by a schema and its valid subschemas,
+// including the items evaluated by its own unevaluatedItems if unevaluated is set
+func (v *validator) evaluatedItems(s *Schema, items []interface{}, ptr string, unevaluated bool) (map[int]bool, error) {
+	s, err := v.resolve(s)
+	if err != nil {
+		return nil, err
+	}
+	u, _ := s.unevaluated()
+	evaluated := map[int]bool{}
+	prefix, rest := s.TupleItems()
+	for n, item := range items {
+		if n < len(prefix) || rest != nil || unevaluated && u != nil {
+			evaluated[n] = true
+			continue
+		}
+		if s.Contains != nil && s.Draft != Draft7 && s.Draft != Draft201909 {
+			ok, err := v.matches(s.Contains, item, ptr+"/"+strconv.Itoa(n))
+			if err != nil {
+				return nil, err
+			}
+			evaluated[n] = ok
+		}
+	}
+
+	subschemas, err := v.applicableSubschemas(s, items, ptr)
+	if err != nil {
+		return nil, err
+	}
+	for _, sch := range subschemas {
+		indices, err := v.evaluatedItems(sch, items, ptr, true)
+		if err != nil {
+			return nil, err
+		}
+		for n := range indices {
+			evaluated[n] = true
+		}
+	}
+	return evaluated, nil
+}
+
+// returns the subschemas of allOf, anyOf, oneOf, if, then, else and dependentSchemas
+// an instance is valid against, whose evaluations count for unevaluated keywords
+func (v *validator) applicableSubschemas(s *Schema, instance interface{}, ptr string) ([]*Schema, error) {
+	var candidates []*Schema
+	candidates = append(candidates, s.AllOf...)
+	candidates = append(candidates, s.AnyOf...)
+	candidates = append(candidates, s.OneOf...)
+	if s.IsRef() {
+		sch, err := v.refTarget(s)
+		if err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, sch)
+	}
+	if s.dynamicRefKey != "" || s.recursiveRefKey != "" {
+		sch, err := v.dynamicTarget(s)
+		if err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, sch)
+	}
+	if s.If != nil {
+		ok, err := v.matches(s.If, instance, ptr)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			candidates = append(candidates, s.If)
+			if s.Then != nil {
+				candidates = append(candidates, s.Then)
+			}
+		} else if s.Else != nil {
+			candidates = append(candidates, s.Else)
+		}
+	}
+	if object, ok := instance.(map[string]interface{}); ok {
+		for _, name := range sortedPropertyNames(object) {
+			if sch, ok := s.DependentSchemas[name]; ok {
+				candidates = append(candidates, sch)
+			}
+			if sch, ok := s.Dependencies[name]; ok {
+				candidates = append(candidates, sch)
+			}
+		}
+	}
+
+	var subschemas []*Schema
+	for _, sch := range candidates {
+		ok, err := v.matches(sch, instance, ptr)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			subschemas = append(subschemas, sch)
+		}
+	}
+	return subschemas, nil
+}
+
+// returns the keyword referencing another schema, "" if the schema has none
+func refKeyword(s *Schema) string {
+	switch {
+	case s.IsRef():
+		return "$ref"
+	case s.dynamicRefKey != "":
+		return "$dynamicRef"
+	case s.recursiveRefKey != "":
+		return "$recursiveRef"
+	}
+	return ""
+}
+
+// returns a schema or, if its draft replaces schemas with $ref by their target, the schema it references
+func (v *validator) resolve(s *Schema) (*Schema, error) {
+	return v.idx.resolveWhile(s, func(s *Schema) bool { return !s.Draft.appliesRefInPlace() })
+}
+
+// returns the schema referenced by the $ref of a schema
+func (v *validator) refTarget(s *Schema) (*Schema, error) {
+	ref, ok := v.idx.Get(s.RefKey)
+	if !ok {
+		return nil, s.Errorf("$ref %v of %v does not exist", s.Ref, s.Pointer)
+	}
+	return ref, nil
+}
+
+// returns the target of the $dynamicRef or $recursiveRef of a schema:
+// the outermost schema of the dynamic scope with the same dynamic anchor as the initial target,
+// or the outermost resource with $recursiveAnchor if the initial target has it, otherwise the initial target
+func (v *validator) dynamicTarget(s *Schema) (*Schema, error) {
+	key := s.dynamicRefKey
+	if key == "" {
+		key = s.recursiveRefKey
+	}
+	target, ok := v.idx.Get(key)
+	if !ok {
+		return nil, fmt.Errorf("jsonschema: %v does not exist in index", key)
+	}
+
+	for _, r := range v.scope {
+		switch {
+		case s.dynamicRefKey != "" && target.DynamicAnchor != "" && target.Draft.defines("$dynamicAnchor") && strings.HasSuffix(key, "#"+target.DynamicAnchor):
+			if sch, ok := r.dynamicAnchors[target.DynamicAnchor]; ok {
+				return sch, nil
+			}
+		case s.dynamicRefKey == "" && target.RecursiveAnchor && target.Draft.defines("$recursiveAnchor"):
+			if r.RecursiveAnchor && r.Draft.defines("$recursiveAnchor") {
+				return r, nil
+			}
+		}
+	}
+	return target, nil
+}
+
+// validates an instance against then if it is valid against if, else against else
+func (v *validator) validateConditional(s *Schema, instance interface{}, ptr string) error {
+	ok, err := v.matches(s.If, instance, ptr)
+	if err != nil {
+		return err
+	}
+	if ok && s.Then != nil {
+		return v.validate(s.Then, instance, ptr)
+	}
+	if !ok && s.Else != nil {
+		return v.validate(s.Else, instance, ptr)
+	}
+	return nil
+}
+
+// validates a number instance against the numeric keywords
+func (v *validator) validateNumber(s *Schema, f *big.Float, ptr string) error {
+	if m, ok := keywordNumber(s, "multipleOf"); ok && !isMultipleOf(f, m) {
+		err := v.fail(newValidationError(s, "multipleOf", ptr, "must be a multiple of %v", formatNumber(m)))
+		if err != nil {
+			return err
+		}
+	}
+	if b, ok := keywordNumber(s, "maximum"); ok && f.Cmp(b) > 0 {
+		err := v.fail(newValidationError(s, "maximum", ptr, "must be <= %v", formatNumber(b)))
+		if err != nil {
+			return err
+		}
+	}
+	if b, ok := keywordNumber(s, "exclusiveMaximum"); ok && f.Cmp(b) >= 0 {
+		err := v.fail(newValidationError(s, "exclusiveMaximum", ptr, "must be < %v", formatNumber(b)))
+		if err != nil {
+			return err
+		}
+	}
+	if b, ok := keywordNumber(s, "minimum"); ok && f.Cmp(b) < 0 {
+		err := v.fail(newValidationError(s, "minimum", ptr, "must be >= %v", formatNumber(b)))
+		if err != nil {
+			return err
+		}
+	}
+	if b, ok := keywordNumber(s, "exclusiveMinimum"); ok && f.Cmp(b) <= 0 {
+		err := v.fail(newValidationError(s, "exclusiveMinimum", ptr, "must be > %v", formatNumber(b)))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validates a string instance against the string keywords
+func (v *validator) validateString(s *Schema, str string, ptr string) error {
+	n := utf8.RuneCountInString(str)
+	if s.MaxLength != nil && n > *s.MaxLength {
+		err := v.fail(newValidationError(s, "maxLength", ptr, "must be at most %v characters long", *s.MaxLength))
+		if err != nil {
+			return err
+		}
+	}
+	if s.MinLength != nil && n < *s.MinLength {
+		err := v.fail(newValidationError(s, "minLength", ptr, "must be at least %v characters long", *s.MinLength))
+		if err != nil {
+			return err
+		}
+	}
+	if s.Pattern != "" {
+		re, err := compilePattern(s.Pattern)
+		if err != nil {
+			return err
+		}
+		if !re.MatchString(str) {
+			err := v.fail(newValidationError(s, "pattern", ptr, "must match %v", s.Pattern))
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// validates an array instance against items and the array keywords
+func (v *validator) validateArray(s *Schema, items []interface{}, ptr string) error {
+	if s.MaxItems != nil && len(items) > *s.MaxItems {
+		err := v.fail(newValidationError(s, "maxItems", ptr, "must have at most %v items", *s.MaxItems))
+		if err != nil {
+			return err
+		}
+	}
+	if s.MinItems != nil && len(items) < *s.MinItems {
+		err := v.fail(newValidationError(s, "minItems", ptr, "must have at least %v items", *s.MinItems))
+		if err != nil {
+			return err
+		}
+	}
+	if s.UniqueItems {
+		if i, j, ok := duplicateInstances(items); ok {
+			err := v.fail(newValidationError(s, "uniqueItems", ptr, "items %v and %v must be unique", i, j))
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if s.Contains != nil {
+		err := v.validateContains(s, items, ptr)
+		if err != nil {
+			return err
+		}
+	}
+
+	prefix, rest := s.TupleItems()
+	for n, item := range items {
+		sch := rest
+		if n < len(prefix) {
+			sch = prefix[n]
+		}
+		if sch == nil {
+			continue
+		}
+		err := v.validate(sch, item, ptr+"/"+strconv.Itoa(n))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validates an array instance against contains, minContains and maxContains
+func (v *validator) validateContains(s *Schema, items []interface{}, ptr string) error {
+	n := 0
+	for i, item := range items {
+		ok, err := v.matches(s.Contains, item, ptr+"/"+strconv.Itoa(i))
+		if err != nil {
+			return err
+		}
+		if ok {
+			n++
+		}
+	}
+
+	min, keyword := 1, "contains"
+	if s.MinContains != nil {
+		min, keyword = *s.MinContains, "minContains"
+	}
+	if n < min {
+		err := v.fail(newValidationError(s, keyword, ptr, "must contain at least %v matching items but contains %v", min, n))
+		if err != nil {
+			return err
+		}
+	}
+	if s.MaxContains != nil && n > *s.MaxContains {
+		err := v.fail(newValidationError(s, "maxContains", ptr, "must contain at most %v matching items but contains %v", *s.MaxContains, n))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validates an object instance against the object keywords besides required and properties
+func (v *validator) validateObject(s *Schema, object map[string]interface{}, ptr string) error {
+	if s.MaxProperties != nil && len(object) > *s.MaxProperties {
+		err := v.fail(newValidationError(s, "maxProperties", ptr, "must have at most %v properties", *s.MaxProperties))
+		if err != nil {
+			return err
+		}
+	}
+	if s.MinProperties != nil && len(object) < *s.MinProperties {
+		err := v.fail(newValidationError(s, "minProperties", ptr, "must have at least %v properties", *s.MinProperties))
+		if err != nil {
+			return err
+		}
+	}
+
+	names := sortedPropertyNames(object)
+	if s.PropertyNames != nil {
+		for _, name := range names {
+			err := v.validate(s.PropertyNames, name, ptr+"/"+EscapeToken(name))
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, name := range names {
+		for _, dep := range s.DependentRequired[name] {
+			if _, ok := object[dep]; ok {
+				continue
+			}
+			err := v.fail(newValidationError(s, "dependentRequired", ptr+"/"+EscapeToken(dep), "missing property %v required by %v", dep, name))
+			if err != nil {
+				return err
+			}
+		}
+		if sch, ok := s.DependentSchemas[name]; ok {
+			err := v.validate(sch, object, ptr)
+			if err != nil {
+				return err
+			}
+		}
+		if sch, ok := s.Dependencies[name]; ok {
+			err := v.validate(sch, object, ptr)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// validates a property of an object instance against properties, patternProperties and additionalProperties
+func (v *validator) validateProperty(s *Schema, name string, instance interface{}, ptr string) error {
+	matched := false
+	if sch, ok := s.Properties[name]; ok {
+		matched = true
+		err := v.validate(sch, instance, ptr)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, pattern := range sortedSchemaNames(s.PatternProperties) {
+		re, err := compilePattern(pattern)
+		if err != nil {
+			return err
+		}
+		if !re.MatchString(name) {
+			continue
+		}
+		matched = true
+		err = v.validate(s.PatternProperties[pattern], instance, ptr)
+		if err != nil {
+			return err
+		}
+	}
+
+	if matched || s.AdditionalProperties == nil {
+		return nil
+	}
+	if ap := s.AdditionalProperties; ap.Boolean != nil && !*ap.Boolean {
+		return v.fail(newValidationError(s, "additionalProperties", ptr, "additional property %v is not allowed", name))
+	}
+	return v.validate(s.AdditionalProperties, instance, ptr)
+}
+
+// reports whether an instance is valid against a schema without recording violations
+func (v *validator) matches(s *Schema, instance interface{}, ptr string) (bool, error) {
+	sub := &validator{idx: v.idx, scope: v.scope, refs: v.refs}
+	err := sub.validate(s, instance, ptr)
+	if _, ok := err.(*ValidationError); ok {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// returns the number of schemas an instance is valid against
+func (v *validator) countMatches(schemas []*Schema, instance interface{}, ptr string) (int, error) {
+	n := 0
+	for _, sch := range schemas {
+		ok, err := v.matches(sch, instance, ptr)
+		if err != nil {
+			return 0, err
+		}
+		if ok {
+			n++
+		}
+	}
+	return n, nil
+}
+
+// decodes a raw JSON document of a single value into an instance, keeping numbers precise as json.Number
+func decodeInstance(doc []byte) (interface{}, error) {
+	var instance interface{}
+	d := json.NewDecoder(bytes.NewReader(doc))
+	d.UseNumber()
+	err := d.Decode(&instance)
+	if err != nil {
+		return nil, fmt.Errorf("jsonschema: %v", err)
+	}
+	if _, err := d.Token(); err != io.EOF {
+		return nil, fmt.Errorf("jsonschema: invalid data after top-level value at offset %v", d.InputOffset())
+	}
+	return instance, nil
+}
+
+// encodes an instance for messages
+func encodeInstance(instance interface{}) string {
+	b, err := json.Marshal(instance)
+	if err != nil {
+		return fmt.Sprintf("%v", instance)
+	}
+	return string(b)
+}
+
+// reports whether two instances are equal as defined in http://json-schema.org/latest/json-schema-core.html#rfc.section.4.2.3
+func equalInstances(a, b interface{}) bool {
+	if fa, ok := numberOf(a); ok {
+		fb, ok := numberOf(b)
+		return ok && fa.Cmp(fb) == 0
+	}
+	switch ta := a.(type) {
+	case map[string]interface{}:
+		tb, ok := b.(map[string]interface{})
+		if !ok || len(ta) != len(tb) {
+			return false
+		}
+		for k, va := range ta {
+			vb, ok := tb[k]
+			if !ok || !equalInstances(va, vb) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		tb, ok := b.([]interface{})
+		if !ok || len(ta) != len(tb) {
+			return false
+		}
+		for i := range ta {
+			if !equalInstances(ta[i], tb[i]) {
+				return false
+			}
+		}
+		return true
+	case nil, bool, string:
+		return a == b
+	}
+	return false
+}
+
+// reports whether an instance equals any of the given instances
+func containsInstance(instances []interface{}, instance interface{}) bool {
+	for _, i := range instances {
+		if equalInstances(i, instance) {
+			return true
+		}
+	}
+	return false
+}
+
+// returns the indices of the first two equal instances
+func duplicateInstances(instances []interface{}) (int, int, bool) {
+	for j := range instances {
+		for i := 0; i < j; i++ {
+			if equalInstances(instances[i], instances[j]) {
+				return i, j, true
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// returns the types allowed by a schema, including schemas created without Parse
+func typesOf(s *Schema) []string {
+	if s.TypeInferred {
+		return nil
+	}
+	if len(s.Types) == 0 && s.Type != "" {
+		return []string{s.Type}
+	}
+	return s.Types
+}
+
+// reports whether an instance is of any of the given JSON Schema types
+func isOfAnyType(instance interface{}, types []string) bool {
+	for _, typ := range types {
+		if isOfType(instance, typ) {
+			return true
+		}
+	}
+	return false
+}
+
+// reports whether an instance is of the given JSON Schema type
+func isOfType(instance interface{}, typ string) bool {
+	t := typeOf(instance)
+	if typ == "integer" {
+		if t != "number" {
+			return false
+		}
+		f, _ := numberOf(instance)
+		return f.IsInt()
+	}
+	return t == typ
+}
+
+// returns the JSON Schema type name of an instance
+func typeOf(instance interface{}) string {
+	switch instance.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	}
+	if _, ok := numberOf(instance); ok {
+		return "number"
+	}
+	return fmt.Sprintf("%T", instance)
+}
+
+// returns the numeric value of an instance, integers of any size are exact and
+// numbers beyond the range of float64 keep their magnitude
+func numberOf(instance interface{}) (*big.Float, bool) {
+	switch n := instance.(type) {
+	case json.Number:
+		if i, ok := new(big.Int).SetString(string(n), 10); ok {
+			return new(big.Float).SetInt(i), true
+		}
+		if f, err := strconv.ParseFloat(string(n), 64); err == nil {
+			return big.NewFloat(f), true
+		}
+		f, _, err := big.ParseFloat(string(n), 10, 64, big.ToNearestEven)
+		return f, err == nil
+	case float64:
+		if math.IsNaN(n) {
+			return nil, false
+		}
+		return big.NewFloat(n), true
+	case float32:
+		return numberOf(float64(n))
+	case int:
+		return new(big.Float).SetInt64(int64(n)), true
+	case int8:
+		return new(big.Float).SetInt64(int64(n)), true
+	case int16:
+		return new(big.Float).SetInt64(int64(n)), true
+	case int32:
+		return new(big.Float).SetInt64(int64(n)), true
+	case int64:
+		return new(big.Float).SetInt64(n), true
+	case uint:
+		return new(big.Float).SetUint64(uint64(n)), true
+	case uint8:
+		return new(big.Float).SetUint64(uint64(n)), true
+	case uint16:
+		return new(big.Float).SetUint64(uint64(n)), true
+	case uint32:
+		return new(big.Float).SetUint64(uint64(n)), true
+	case uint64:
+		return new(big.Float).SetUint64(n), true
+	}
+	return nil, false
+}
+
+// returns the exact value of a numeric keyword, false if the schema does not contain it
+func keywordNumber(s *Schema, keyword string) (*big.Float, bool) {
+	n, ok := s.Number(keyword)
+	if !ok {
+		return nil, false
+	}
+	return numberOf(n)
+}
+
+// reports whether a number is a multiple of m, exactly for integers and within a small tolerance otherwise
+func isMultipleOf(f, m *big.Float) bool {
+	if f.IsInt() && m.IsInt() {
+		i, _ := f.Int(nil)
+		d, _ := m.Int(nil)
+		return d.Sign() != 0 && new(big.Int).Rem(i, d).Sign() == 0
+	}
+	x, _ := f.Float64()
+	y, _ := m.Float64()
+	q := x / y
+	return math.Abs(q-math.Floor(q+0.5)) <= 1e-9
+}
+
+// formats a number for messages
+func formatNumber(f *big.Float) string {
+	return f.Text('g', -1)
+}
+
+// compiled patterns shared by all validators
+var patterns sync.Map
+
+// returns the compiled regular expression of a pattern
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	if re, ok := patterns.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("jsonschema: invalid pattern %v: %v", pattern, err)
+	}
+	patterns.Store(pattern, re)
+	return re, nil
+}
+
+// returns the names of schemas sorted by alphabet
+func sortedSchemaNames(m Schemas) []string {
+	names := make([]string, 0, len(m))
+	for k := range m {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// returns the property names of an object instance sorted by alphabet
+func sortedPropertyNames(m map[string]interface{}) []string {
+	names := make([]string, 0, len(m))
+	for k := range m {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
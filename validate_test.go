@@ -0,0 +1,557 @@
+package jsonschema
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/tfkhsr/jsonschema/fixture"
+)
+
+func TestValidateJSON(t *testing.T) {
+	table := []struct {
+		RawSchema string
+		Pointer   string
+		Doc       string
+		Valid     bool
+	}{
+		{fixture.TestSchemaWithDefinitions, "#/definitions/movie", `{"id": "1", "name": "Alien"}`, true},
+		{fixture.TestSchemaWithDefinitions, "#/definitions/movie", `{"id": "1"}`, false},
+		{fixture.TestSchemaWithDefinitions, "#/definitions/movie", `{"id": 1, "name": "Alien"}`, false},
+		{fixture.TestSchemaWithDefinitions, "#/definitions/movie", `{"id": "1", "name": "Alien", "year": 1979}`, true},
+		{fixture.TestSchemaWithDefinitions, "#/definitions/movie", `{"id": "1", "name": "Alien", "year": 1979.5}`, false},
+		{fixture.TestSchemaWithDefinitions, "#/definitions/movie", `{"id": "1", "name": "Alien", "year": 1979.0}`, true},
+		{fixture.TestSchemaWithDefinitions, "#/definitions/movie", `{"id": "1", "name": "Alien", "actor": {"name": 7}}`, false},
+		{fixture.TestSchemaWithDefinitions, "#/definitions/movie", `{"id": "1", "name": "Alien", "categories": ["horror"]}`, true},
+		{fixture.TestSchemaWithDefinitions, "#/definitions/movie", `{"id": "1", "name": "Alien", "categories": ["horror", 1]}`, false},
+		{fixture.TestSchemaWithDefinitions, "#/definitions/movie", `{"id": "1", "name": "Alien", "categories": "horror"}`, false},
+		{fixture.TestSchemaWithDefinitions, "#/definitions/movie", `[]`, false},
+		{fixture.TestSchemaWithDefinitions, "#/definitions/categories", `[]`, true},
+		{fixture.TestSchemaDirect, "#", `{"id": "1", "name": "Alien"}`, true},
+		{fixture.TestSchemaDirect, "#", `{"id": "1"}`, false},
+		{fixture.TestSchemaRequiredValidation, "#/definitions/movie", `{"id": "1", "actors": []}`, true},
+		{fixture.TestSchemaRequiredValidation, "#/definitions/movie", `{"id": "1", "actors": [{"name": "John Snow"}]}`, false},
+		{fixture.TestSchemaRequiredValidation, "#/definitions/movie", `{"id": "1", "actors": [{"name": "John Snow", "location": {}}]}`, false},
+		{fixture.TestSchemaRequiredValidation, "#/definitions/movie", `{"id": "1", "actors": [{"name": "John Snow", "location": {"name": "Winterfell"}}]}`, true},
+		{fixture.TestSchemaPrimitiveTypes, "#/definitions/null", `null`, true},
+		{fixture.TestSchemaPrimitiveTypes, "#/definitions/null", `false`, false},
+		{fixture.TestSchemaPrimitiveTypes, "#/definitions/boolean", `false`, true},
+		{fixture.TestSchemaPrimitiveTypes, "#/definitions/boolean", `"false"`, false},
+		{fixture.TestSchemaPrimitiveTypes, "#/definitions/number", `1`, true},
+		{fixture.TestSchemaPrimitiveTypes, "#/definitions/number", `1.5e3`, true},
+		{fixture.TestSchemaPrimitiveTypes, "#/definitions/integer", `1e3`, true},
+		{fixture.TestSchemaPrimitiveTypes, "#/definitions/integer", `1.5`, false},
+		{fixture.TestSchemaPrimitiveTypes, "#/definitions/string", `""`, true},
+		{fixture.TestSchemaPrimitiveTypes, "#/definitions/object", `{}`, true},
+		{fixture.TestSchemaPrimitiveTypes, "#/definitions/array", `{}`, false},
+		{fixture.TestSchemaEnumValidation, "#/definitions/movie", `{"id": "1", "status": "released", "rating": 5, "kind": "movie"}`, true},
+		{fixture.TestSchemaEnumValidation, "#/definitions/movie", `{"id": "1", "status": "cancelled"}`, false},
+		{fixture.TestSchemaEnumValidation, "#/definitions/movie", `{"id": "1", "rating": 6}`, false},
+		{fixture.TestSchemaEnumValidation, "#/definitions/movie", `{"id": "1", "rating": 5.0}`, true},
+		{fixture.TestSchemaEnumValidation, "#/definitions/movie", `{"id": "1", "kind": "series"}`, false},
+		{fixture.TestSchemaCompositionValidation, "#/definitions/cat", `{"name": "Tom", "lives": 9}`, true},
+		{fixture.TestSchemaCompositionValidation, "#/definitions/cat", `{"lives": 9}`, false},
+		{fixture.TestSchemaCompositionValidation, "#/definitions/cat", `{"name": "Tom"}`, false},
+		{fixture.TestSchemaCompositionValidation, "#/definitions/pet", `{"name": "Tom", "lives": 9}`, true},
+		{fixture.TestSchemaCompositionValidation, "#/definitions/pet", `{"name": "Rex", "breed": "Beagle"}`, true},
+		{fixture.TestSchemaCompositionValidation, "#/definitions/pet", `{"name": "Rex", "breed": "Beagle", "lives": 9}`, false},
+		{fixture.TestSchemaCompositionValidation, "#/definitions/pet", `{"name": "Rex"}`, false},
+		{fixture.TestSchemaCompositionValidation, "#/definitions/tag", `"new"`, true},
+		{fixture.TestSchemaCompositionValidation, "#/definitions/tag", `1`, true},
+		{fixture.TestSchemaCompositionValidation, "#/definitions/tag", `1.5`, false},
+		{fixture.TestSchemaCompositionValidation, "#/definitions/owner", `{"name": "John Snow", "tags": ["a", 1]}`, true},
+		{fixture.TestSchemaCompositionValidation, "#/definitions/owner", `{"name": "nobody"}`, false},
+		{fixture.TestSchemaCompositionValidation, "#/definitions/owner", `{"tags": [true]}`, false},
+		{fixture.TestSchemaAdditionalPropertiesValidation, "#/definitions/movie", `{"id": "1", "labels": {"genre": "horror"}}`, true},
+		{fixture.TestSchemaAdditionalPropertiesValidation, "#/definitions/movie", `{"id": "1", "year": 1979}`, false},
+		{fixture.TestSchemaAdditionalPropertiesValidation, "#/definitions/movie", `{"id": "1", "labels": {"genre": 1}}`, false},
+		{fixture.TestSchemaAdditionalPropertiesValidation, "#/definitions/actor", `{"name": "John Snow", "home": {"name": "Winterfell"}}`, true},
+		{fixture.TestSchemaAdditionalPropertiesValidation, "#/definitions/actor", `{"name": "John Snow", "home": {}}`, false},
+		{fixture.TestSchemaAdditionalPropertiesValidation, "#/definitions/scores", `{"alien": 9, "heat": 8}`, true},
+		{fixture.TestSchemaAdditionalPropertiesValidation, "#/definitions/scores", `{"alien": 9.5}`, false},
+		{fixture.TestSchemaAdditionalPropertiesValidation, "#/definitions/scores", `{"Alien": 9}`, false},
+		{fixture.TestSchemaConstraintValidation, "#/definitions/movie", `{"id": "alien-1979", "title": "Alien", "year": 1979, "rating": 8.5, "runtime": 115}`, true},
+		{fixture.TestSchemaEscapedPointers, "#/definitions/file", `{"mime/type": "text/plain", "size~bytes": 1, "display name": "a.txt"}`, true},
+		{fixture.TestSchemaBooleanSchemas, "#/definitions/movie", `{"id": "1", "meta": {"a": 1}, "rating": 8, "tags": [1, "a"], "credits": [], "pair": ["a", 1], "labels": {"a": 1}}`, true},
+		{fixture.TestSchemaBooleanSchemas, "#/definitions/movie", `{"id": "1", "legacy": true}`, false},
+		{fixture.TestSchemaBooleanSchemas, "#/definitions/movie", `{"id": "1", "sequel": "Aliens"}`, false},
+		{fixture.TestSchemaBooleanSchemas, "#/definitions/movie", `{"id": "1", "credits": ["John Snow"]}`, false},
+		{fixture.TestSchemaBooleanSchemas, "#/definitions/movie", `{"id": "1", "pair": ["a", 1, 2]}`, false},
+		{fixture.TestSchemaBooleanSchemas, "#/definitions/always", `null`, true},
+		{fixture.TestSchemaBooleanSchemas, "#/definitions/never", `null`, false},
+		{fixture.TestSchemaEscapedPointers, "#/definitions/file", `{"mime/type": "text/plain", "size~bytes": "1"}`, false},
+		{fixture.TestSchemaConstraintValidation, "#/definitions/movie", `{"id": "Alien"}`, false},
+		{fixture.TestSchemaConstraintValidation, "#/definitions/movie", `{"id": "1", "title": ""}`, false},
+		{fixture.TestSchemaConstraintValidation, "#/definitions/movie", `{"id": "1", "title": "Ünïcödé"}`, true},
+		{fixture.TestSchemaConstraintValidation, "#/definitions/movie", `{"id": "1", "title": "Alien: Resurrection"}`, false},
+		{fixture.TestSchemaConstraintValidation, "#/definitions/movie", `{"id": "1", "year": 1899}`, false},
+		{fixture.TestSchemaConstraintValidation, "#/definitions/movie", `{"id": "1", "year": 2100}`, true},
+		{fixture.TestSchemaConstraintValidation, "#/definitions/movie", `{"id": "1", "rating": 0}`, false},
+		{fixture.TestSchemaConstraintValidation, "#/definitions/movie", `{"id": "1", "rating": 10}`, false},
+		{fixture.TestSchemaConstraintValidation, "#/definitions/movie", `{"id": "1", "rating": 0.3}`, true},
+		{fixture.TestSchemaConstraintValidation, "#/definitions/movie", `{"id": "1", "rating": 0.35}`, false},
+		{fixture.TestSchemaConstraintValidation, "#/definitions/movie", `{"id": "1", "runtime": 112}`, false},
+		{fixture.TestSchemaConstraintValidation, "#/definitions/movie", `{"id": "1", "score": 99}`, true},
+		{fixture.TestSchemaConstraintValidation, "#/definitions/movie", `{"id": "1", "score": 100}`, false},
+		{fixture.TestSchemaArrayValidation, "#/definitions/roles", `[{"name": "root", "permissions": "all"}, {"name": "guest"}]`, true},
+		{fixture.TestSchemaArrayValidation, "#/definitions/roles", `[]`, false},
+		{fixture.TestSchemaArrayValidation, "#/definitions/roles", `[{"name": "guest"}]`, false},
+		{fixture.TestSchemaArrayValidation, "#/definitions/roles", `[{"name": "root", "permissions": "all"}, {"name": "guest"}, {"name": "guest"}]`, false},
+		{fixture.TestSchemaArrayValidation, "#/definitions/roles", `[{"name": "a", "permissions": "all"}, {"name": "b"}, {"name": "c"}, {"name": "d"}]`, false},
+		{fixture.TestSchemaArrayValidation, "#/definitions/tags", `["new", "horror"]`, true},
+		{fixture.TestSchemaArrayValidation, "#/definitions/tags", `["horror"]`, true},
+		{fixture.TestSchemaArrayValidation, "#/definitions/tags", `["new", "horror", "new"]`, false},
+		{fixture.TestSchemaArrayValidation, "#/definitions/scores", `[1, 2.0]`, true},
+		{fixture.TestSchemaArrayValidation, "#/definitions/scores", `[1, 1.0]`, false},
+		{fixture.TestSchemaArrayValidation, "#/definitions/scores", `[1]`, false},
+		{fixture.TestSchemaArrayValidation, "#/definitions/cast", `[{"name": "John Snow"}, {"name": "Arya Stark"}]`, true},
+		{fixture.TestSchemaArrayValidation, "#/definitions/cast", `[{"name": "John Snow"}, {}]`, false},
+		{fixture.TestSchemaObjectValidation, "#/definitions/movie", `{"id": "1", "title": "Alien"}`, true},
+		{fixture.TestSchemaObjectValidation, "#/definitions/movie", `{}`, false},
+		{fixture.TestSchemaObjectValidation, "#/definitions/movie", `{"id": "1", "title": "Alien", "sequel": "Aliens", "prequel": "Prometheus"}`, false},
+		{fixture.TestSchemaObjectValidation, "#/definitions/movie", `{"sequel": "Aliens"}`, false},
+		{fixture.TestSchemaObjectValidation, "#/definitions/movie", `{"prequel": "Prometheus"}`, false},
+		{fixture.TestSchemaObjectValidation, "#/definitions/movie", `{"prequel": "Prometheus", "id": "1"}`, true},
+		{fixture.TestSchemaObjectValidation, "#/definitions/labels", `{"genre": "horror", "year": "1979"}`, true},
+		{fixture.TestSchemaObjectValidation, "#/definitions/labels", `{"genre": "horror"}`, false},
+		{fixture.TestSchemaObjectValidation, "#/definitions/labels", `{"Genre": "horror"}`, false},
+		{fixture.TestSchemaObjectValidation, "#/definitions/labels", `{"languages": "en"}`, false},
+		{fixture.TestSchemaObjectValidation, "#/definitions/series", `{"season": 1, "episode": 2, "network": "HBO"}`, true},
+		{fixture.TestSchemaObjectValidation, "#/definitions/series", `{"episode": 2}`, false},
+		{fixture.TestSchemaObjectValidation, "#/definitions/series", `{"network": "HBO"}`, false},
+		{fixture.TestSchemaObjectValidation, "#/definitions/series", `{"network": "HBO", "season": 0}`, false},
+		{fixture.TestSchemaObjectValidation, "#/definitions/series", `{"season": 0}`, true},
+		{fixture.TestSchemaConditionalValidation, "#/definitions/payment", `{"kind": "card", "cardNumber": "4111111111111111", "amount": 5000}`, true},
+		{fixture.TestSchemaConditionalValidation, "#/definitions/payment", `{"kind": "card", "amount": 5}`, false},
+		{fixture.TestSchemaConditionalValidation, "#/definitions/payment", `{"kind": "card", "cardNumber": "4111"}`, false},
+		{fixture.TestSchemaConditionalValidation, "#/definitions/payment", `{"kind": "cash", "amount": 5}`, true},
+		{fixture.TestSchemaConditionalValidation, "#/definitions/payment", `{"kind": "cash", "amount": 5000}`, false},
+		{fixture.TestSchemaConditionalValidation, "#/definitions/payment", `{"amount": 5000}`, false},
+		{fixture.TestSchemaConditionalValidation, "#/definitions/address", `{"country": "US", "postalCode": "12345"}`, true},
+		{fixture.TestSchemaConditionalValidation, "#/definitions/address", `{"country": "US", "postalCode": "SW1A 1AA"}`, false},
+		{fixture.TestSchemaConditionalValidation, "#/definitions/address", `{"country": "GB", "postalCode": "SW1A 1AA"}`, true},
+		{fixture.TestSchemaConditionalValidation, "#/definitions/address", `{"postalCode": "SW1A 1AA"}`, false},
+		{fixture.TestSchemaNullableValidation, "#/definitions/profile", `{"nickname": "jon", "age": 30, "address": {"city": "Winterfell"}, "score": 10}`, true},
+		{fixture.TestSchemaNullableValidation, "#/definitions/profile", `{"nickname": null, "age": null, "address": null, "score": "A"}`, true},
+		{fixture.TestSchemaNullableValidation, "#/definitions/profile", `{}`, false},
+		{fixture.TestSchemaNullableValidation, "#/definitions/profile", `{"nickname": "jonathan"}`, false},
+		{fixture.TestSchemaNullableValidation, "#/definitions/profile", `{"nickname": null, "age": "30"}`, false},
+		{fixture.TestSchemaNullableValidation, "#/definitions/profile", `{"nickname": null, "address": {}}`, false},
+		{fixture.TestSchemaNullableValidation, "#/definitions/profile", `{"nickname": null, "score": null}`, false},
+		{fixture.TestSchemaNullableValidation, "#/definitions/profile", `{"nickname": null, "score": 1.5}`, false},
+		{fixture.TestSchemaNullableValidation, "#/definitions/profile", `{"nickname": null, "score": 101}`, false},
+		{fixture.TestSchemaNullableValidation, "#/definitions/profile", `{"nickname": null, "score": ""}`, false},
+		{fixture.TestSchemaIDAndAnchor, "#/definitions/person", `{"name": "John", "address": {"city": "Winterfell"}, "city": "Winterfell", "zip": "12345", "age": 30}`, true},
+		{fixture.TestSchemaIDAndAnchor, "#/definitions/person", `{"name": ""}`, false},
+		{fixture.TestSchemaIDAndAnchor, "#/definitions/person", `{"name": "John", "address": {}}`, false},
+		{fixture.TestSchemaIDAndAnchor, "#/definitions/person", `{"name": "John", "city": 1}`, false},
+		{fixture.TestSchemaIDAndAnchor, "#/definitions/person", `{"name": "John", "zip": "123"}`, false},
+		{fixture.TestSchemaIDAndAnchor, "#/definitions/person", `{"name": "John", "age": -1}`, false},
+		{fixture.TestSchemaDraft202012, "#/$defs/point", `[1, 2]`, true},
+		{fixture.TestSchemaDraft202012, "#/$defs/point", `[1, "2"]`, false},
+		{fixture.TestSchemaDraft202012, "#/$defs/point", `[1, 2, 3]`, false},
+		{fixture.TestSchemaDraft202012, "#/$defs/measurement", `["temperature", 20.5, 21]`, true},
+		{fixture.TestSchemaDraft202012, "#/$defs/measurement", `["temperature", "20.5"]`, false},
+		{fixture.TestSchemaDraft202012, "#/$defs/person", `{"name": "John", "age": 30}`, true},
+		{fixture.TestSchemaDraft202012, "#/$defs/person", `{"name": "John", "age": 30, "email": "john@example.com"}`, false},
+		{fixture.TestSchemaDraft7, "#/definitions/point", `[1, 2]`, true},
+		{fixture.TestSchemaDraft7, "#/definitions/point", `[1, "2"]`, false},
+		{fixture.TestSchemaDraft7, "#/definitions/point", `[1, 2, 3]`, false},
+		{fixture.TestSchemaDraft7, "#/definitions/names", `["John", "Jane"]`, true},
+		{fixture.TestSchemaDraft7, "#/definitions/names", `[1]`, false},
+	}
+	for _, ts := range table {
+		idx, err := Parse([]byte(ts.RawSchema))
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = idx.ValidateJSON(ts.Pointer, []byte(ts.Doc))
+		if ts.Valid && err != nil {
+			t.Fatalf("%v should be valid against %v but is not: %v", ts.Doc, ts.Pointer, err)
+		}
+		if !ts.Valid && err == nil {
+			t.Fatalf("%v should be invalid against %v but is not", ts.Doc, ts.Pointer)
+		}
+	}
+}
+
+func TestValidateJSONUnknownPointer(t *testing.T) {
+	idx, err := Parse([]byte(fixture.TestSchemaWithDefinitions))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = idx.ValidateJSON("#/definitions/unknown", []byte(`{}`))
+	if err == nil {
+		t.Fatal("validation against an unknown pointer should fail")
+	}
+}
+
+func TestValidateJSONTrailingData(t *testing.T) {
+	idx, err := Parse([]byte(`{}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, doc := range []string{`{} garbage`, `{} []`, `1 2`, `"a"}`} {
+		if err := idx.ValidateJSON("#", []byte(doc)); err == nil {
+			t.Fatalf("%v should be rejected for data after the top-level value", doc)
+		}
+	}
+	if err := idx.ValidateJSON("#", []byte(" {}\n")); err != nil {
+		t.Fatalf("whitespace after the top-level value should be allowed but validation returned %v", err)
+	}
+}
+
+func TestValidateNumbers(t *testing.T) {
+	table := []struct {
+		RawSchema string
+		Doc       string
+		Valid     bool
+	}{
+		{`{"type": "number"}`, `1e400`, true},
+		{`{"type": "integer"}`, `1e400`, true},
+		{`{"type": "integer"}`, `-1e400`, true},
+		{`{"maximum": 1e300}`, `1e400`, false},
+		{`{"minimum": -1e300}`, `-1e400`, false},
+		{`{"maximum": 9007199254740992}`, `9007199254740993`, false},
+		{`{"maximum": 9007199254740992}`, `9007199254740992`, true},
+		{`{"exclusiveMinimum": 9007199254740992}`, `9007199254740993`, true},
+		{`{"exclusiveMinimum": 9007199254740992}`, `9007199254740992`, false},
+		{`{"multipleOf": 2}`, `9007199254740993`, false},
+		{`{"multipleOf": 2}`, `9007199254740994`, true},
+		{`{"multipleOf": 0.1}`, `0.3`, true},
+		{`{"minimum": 0.1}`, `0.1`, true},
+		{`{"const": 9007199254740992}`, `9007199254740993`, false},
+		{`{"maximum": 9007199254740993}`, `9007199254740993`, true},
+		{`{"maximum": 9007199254740993}`, `9007199254740994`, false},
+		{`{"exclusiveMaximum": 9007199254740993}`, `9007199254740992`, true},
+		{`{"exclusiveMaximum": 9007199254740993}`, `9007199254740993`, false},
+		{`{"minimum": 9007199254740993}`, `9007199254740993`, true},
+		{`{"minimum": 9007199254740993}`, `9007199254740992`, false},
+		{`{"exclusiveMinimum": 9007199254740993}`, `9007199254740994`, true},
+		{`{"multipleOf": 9007199254740993}`, `18014398509481986`, true},
+		{`{"multipleOf": 9007199254740993}`, `18014398509481984`, false},
+		{`{"const": 9007199254740993}`, `9007199254740993`, true},
+		{`{"const": 9007199254740993}`, `9007199254740992`, false},
+		{`{"enum": [1, 9007199254740993]}`, `9007199254740993`, true},
+		{`{"enum": [1, 9007199254740993]}`, `9007199254740992`, false},
+	}
+	for _, ts := range table {
+		idx, err := Parse([]byte(ts.RawSchema))
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = idx.ValidateJSON("#", []byte(ts.Doc))
+		if ts.Valid && err != nil {
+			t.Fatalf("%v should be valid against %v but is not: %v", ts.Doc, ts.RawSchema, err)
+		}
+		if !ts.Valid && err == nil {
+			t.Fatalf("%v should be invalid against %v but is not", ts.Doc, ts.RawSchema)
+		}
+	}
+}
+
+func TestValidateRefWithKeywords(t *testing.T) {
+	schema := `{
+		"$schema": "%[1]v",
+		"%[2]v": {"a": {"type": "object", "properties": {"x": {"type": "string"}}}, "t": {"prefixItems": [{}]}},
+		"properties": {
+			"object": {"$ref": "#/%[2]v/a", "properties": {"y": {"type": "integer"}}, "required": ["y"], "unevaluatedProperties": false},
+			"tuple": {"$ref": "#/%[2]v/t", "unevaluatedItems": false}
+		}
+	}`
+	draft202012 := fmt.Sprintf(schema, "https://json-schema.org/draft/2020-12/schema", "$defs")
+	draft201909 := fmt.Sprintf(schema, "https://json-schema.org/draft/2019-09/schema", "$defs")
+	draft7 := fmt.Sprintf(schema, "http://json-schema.org/draft-07/schema#", "definitions")
+	table := []struct {
+		RawSchema string
+		Doc       string
+		Valid     bool
+	}{
+		{draft202012, `{"object": {"x": "1", "y": 2}}`, true},
+		{draft202012, `{"object": {"x": "1"}}`, false},
+		{draft202012, `{"object": {"x": "1", "y": 2, "z": 3}}`, false},
+		{draft202012, `{"object": {"x": 1, "y": 2}}`, false},
+		{draft202012, `{"tuple": [1]}`, true},
+		{draft202012, `{"tuple": [1, 2]}`, false},
+		{draft201909, `{"object": {"x": "1", "y": 2}}`, true},
+		{draft201909, `{"object": {"x": "1"}}`, false},
+		{draft201909, `{"object": {"x": "1", "y": 2, "z": 3}}`, false},
+
+		// before 2019-09 the keywords next to $ref are ignored
+		{draft7, `{"object": {"x": "1"}}`, true},
+		{draft7, `{"object": {"x": 1}}`, false},
+	}
+	for _, ts := range table {
+		idx, err := Parse([]byte(ts.RawSchema))
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = idx.ValidateJSON("#", []byte(ts.Doc))
+		if ts.Valid && err != nil {
+			t.Fatalf("%v should be valid against %v but is not: %v", ts.Doc, ts.RawSchema, err)
+		}
+		if !ts.Valid && err == nil {
+			t.Fatalf("%v should be invalid against %v but is not", ts.Doc, ts.RawSchema)
+		}
+	}
+}
+
+func TestValidateGoInstance(t *testing.T) {
+	idx, err := Parse([]byte(fixture.TestSchemaWithDefinitions))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := idx.schemas["#/definitions/movie"]
+
+	valid := map[string]interface{}{"id": "1", "name": "Alien", "year": 1979}
+	if err := s.Validate(idx, valid); err != nil {
+		t.Fatalf("%v should be valid but is not: %v", valid, err)
+	}
+
+	invalid := map[string]interface{}{"id": "1", "name": "Alien", "year": "1979"}
+	if err := s.Validate(idx, invalid); err == nil {
+		t.Fatalf("%v should be invalid but is not", invalid)
+	}
+}
+
+func TestValidationError(t *testing.T) {
+	table := []struct {
+		RawSchema       string
+		Pointer         string
+		Doc             string
+		InstancePointer string
+		SchemaPointer   string
+		Keyword         string
+	}{
+		{fixture.TestSchemaWithDefinitions, "#/definitions/movie", `{"id": "1"}`, "/name", "#/definitions/movie", "required"},
+		{fixture.TestSchemaWithDefinitions, "#/definitions/movie", `{"id": "1", "name": "Alien", "year": "1979"}`, "/year", "#/definitions/movie/properties/year", "type"},
+		{fixture.TestSchemaWithDefinitions, "#/definitions/movie", `{"id": "1", "name": "Alien", "categories": ["horror", 1]}`, "/categories/1", "#/definitions/categories/items", "type"},
+		{fixture.TestSchemaRequiredValidation, "#/definitions/movie", `{"id": "1", "actors": [{"name": "John Snow", "location": {}}]}`, "/actors/0/location/name", "#/definitions/location", "required"},
+		{fixture.TestSchemaPrimitiveTypes, "#/definitions/string", `1`, "", "#/definitions/string", "type"},
+		{fixture.TestSchemaEnumValidation, "#/definitions/movie", `{"id": "1", "rating": 0}`, "/rating", "#/definitions/rating", "enum"},
+		{fixture.TestSchemaEnumValidation, "#/definitions/movie", `{"id": "1", "kind": "series"}`, "/kind", "#/definitions/movie/properties/kind", "const"},
+		{fixture.TestSchemaAdditionalPropertiesValidation, "#/definitions/movie", `{"id": "1", "year": 1979}`, "/year", "#/definitions/movie", "additionalProperties"},
+		{fixture.TestSchemaAdditionalPropertiesValidation, "#/definitions/actor", `{"home": {}}`, "/home/name", "#/definitions/location", "required"},
+		{fixture.TestSchemaAdditionalPropertiesValidation, "#/definitions/scores", `{"alien": 9.5}`, "/alien", "#/definitions/scores/patternProperties/%5E%5Ba-z%5D+$", "type"},
+		{fixture.TestSchemaEscapedPointers, "#/definitions/file", `{}`, "/mime~1type", "#/definitions/file", "required"},
+		{fixture.TestSchemaBooleanSchemas, "#/definitions/movie", `{"id": "1", "legacy": true}`, "/legacy", "#/definitions/movie/properties/legacy", "false"},
+		{fixture.TestSchemaBooleanSchemas, "#/definitions/movie", `{"id": "1", "pair": ["a", 1, 2]}`, "/pair/2", "#/definitions/movie/properties/pair/additionalItems", "false"},
+		{fixture.TestSchemaEscapedPointers, "#/definitions/file", `{"mime/type": "text/plain", "size~bytes": "1"}`, "/size~0bytes", "#/definitions/x~0y", "type"},
+		{fixture.TestSchemaConstraintValidation, "#/definitions/movie", `{"id": "Alien"}`, "/id", "#/definitions/movie/properties/id", "pattern"},
+		{fixture.TestSchemaConstraintValidation, "#/definitions/movie", `{"id": "1", "runtime": 0}`, "/runtime", "#/definitions/minutes", "exclusiveMinimum"},
+		{fixture.TestSchemaConstraintValidation, "#/definitions/movie", `{"id": "1", "year": 2101}`, "/year", "#/definitions/movie/properties/year", "maximum"},
+		{fixture.TestSchemaArrayValidation, "#/definitions/movie", `{"roles": [{"name": "guest"}]}`, "/roles", "#/definitions/roles", "contains"},
+		{fixture.TestSchemaArrayValidation, "#/definitions/movie", `{"tags": ["new", "new"]}`, "/tags", "#/definitions/tags", "maxContains"},
+		{fixture.TestSchemaArrayValidation, "#/definitions/scores", `[1, 2, 1]`, "", "#/definitions/scores", "uniqueItems"},
+		{fixture.TestSchemaArrayValidation, "#/definitions/cast", `[{"name": "John Snow"}]`, "", "#/definitions/cast", "minContains"},
+		{fixture.TestSchemaObjectValidation, "#/definitions/movie", `{"sequel": "Aliens"}`, "/title", "#/definitions/movie", "dependentRequired"},
+		{fixture.TestSchemaObjectValidation, "#/definitions/movie", `{"prequel": "Prometheus"}`, "/id", "#/definitions/movie/dependentSchemas/prequel", "required"},
+		{fixture.TestSchemaObjectValidation, "#/definitions/labels", `{"Genre": "horror"}`, "/Genre", "#/definitions/labels/propertyNames", "pattern"},
+		{fixture.TestSchemaObjectValidation, "#/definitions/series", `{"network": "HBO", "season": 0}`, "/season", "#/definitions/series/dependencies/network/properties/season", "minimum"},
+		{fixture.TestSchemaConditionalValidation, "#/definitions/payment", `{"kind": "card"}`, "/cardNumber", "#/definitions/payment/then", "required"},
+		{fixture.TestSchemaConditionalValidation, "#/definitions/payment", `{"kind": "cash", "amount": 5000}`, "/amount", "#/definitions/payment/else/properties/amount", "maximum"},
+		{fixture.TestSchemaNullableValidation, "#/definitions/profile", `{"nickname": null, "score": true}`, "/score", "#/definitions/score", "type"},
+		{fixture.TestSchemaDraft202012, "#/$defs/point", `[1, 2, 3]`, "/2", "#/$defs/point/items", "false"},
+		{fixture.TestSchemaDraft202012, "#/$defs/person", `{"name": "John", "email": "john@example.com"}`, "/email", "#/$defs/person", "unevaluatedProperties"},
+		{fixture.TestSchemaDraft202012, "#/$defs/measurement", `["temperature", "20.5"]`, "/1", "#/$defs/measurement/unevaluatedItems", "type"},
+		{fixture.TestSchemaDraft7, "#/definitions/point", `[1, "2"]`, "/1", "#/definitions/point/items/1", "type"},
+	}
+	for _, ts := range table {
+		idx, err := Parse([]byte(ts.RawSchema))
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = idx.ValidateJSON(ts.Pointer, []byte(ts.Doc))
+		verr, ok := err.(*ValidationError)
+		if !ok {
+			t.Fatalf("%v should produce a *ValidationError but produced '%v'", ts.Doc, err)
+		}
+		if verr.InstancePointer != ts.InstancePointer {
+			t.Fatalf("instance pointer of %v should be '%v' but is '%v'", ts.Doc, ts.InstancePointer, verr.InstancePointer)
+		}
+		if verr.SchemaPointer != ts.SchemaPointer {
+			t.Fatalf("schema pointer of %v should be '%v' but is '%v'", ts.Doc, ts.SchemaPointer, verr.SchemaPointer)
+		}
+		if verr.Keyword != ts.Keyword {
+			t.Fatalf("keyword of %v should be '%v' but is '%v'", ts.Doc, ts.Keyword, verr.Keyword)
+		}
+	}
+}
+
+func TestValidateCyclicSubschemas(t *testing.T) {
+	table := []struct {
+		RawSchema string
+		Keyword   string
+	}{
+		{`{"definitions": {"a": {"allOf": [{"$ref": "#/definitions/a"}]}}}`, "$ref"},
+		{`{"definitions": {"a": {"allOf": [{"$ref": "#/definitions/b"}]}, "b": {"oneOf": [{"$ref": "#/definitions/a"}]}}}`, "oneOf"},
+		{`{"definitions": {"a": {"anyOf": [{"$ref": "#/definitions/a"}, {"type": "string"}]}}}`, "anyOf"},
+		{`{"$schema": "https://json-schema.org/draft/2020-12/schema", "$defs": {"a": {"$ref": "#/$defs/b"}, "b": {"allOf": [{"$ref": "#/$defs/a"}]}}, "$ref": "#/$defs/a"}`, "$ref"},
+	}
+	for _, ts := range table {
+		idx, err := Parse([]byte(ts.RawSchema))
+		if err != nil {
+			t.Fatal(err)
+		}
+		ptr := "#/definitions/a"
+		if _, ok := idx.Get(ptr); !ok {
+			ptr = "#"
+		}
+		err = idx.ValidateJSON(ptr, []byte(`1`))
+		if e, ok := err.(*ValidationError); !ok || e.Keyword != ts.Keyword {
+			t.Fatalf("1 should fail %v of %v but failed with %v", ts.Keyword, ts.RawSchema, err)
+		}
+	}
+}
+
+func TestValidateAllErrors(t *testing.T) {
+	idx, err := Parse([]byte(fixture.TestSchemaRequiredValidation))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	doc := `{"actors": [{"name": 1, "location": {}}, {"location": {"name": "Winterfell"}}]}`
+	err = idx.ValidateJSONWithOptions("#/definitions/movie", []byte(doc), ValidateOptions{AllErrors: true})
+	verrs, ok := err.(ValidationErrors)
+	if !ok {
+		t.Fatalf("%v should produce ValidationErrors but produced '%v'", doc, err)
+	}
+
+	pointers := []string{"/id", "/actors/0/location/name", "/actors/0/name", "/actors/1/name"}
+	if len(verrs) != len(pointers) {
+		t.Fatalf("%v should produce %v errors but produced %v: %v", doc, len(pointers), len(verrs), verrs)
+	}
+	for i, p := range pointers {
+		if verrs[i].InstancePointer != p {
+			t.Fatalf("error %v of %v should be at '%v' but is at '%v'", i, doc, p, verrs[i].InstancePointer)
+		}
+	}
+
+	valid := `{"id": "1", "actors": []}`
+	err = idx.ValidateJSONWithOptions("#/definitions/movie", []byte(valid), ValidateOptions{AllErrors: true})
+	if err != nil {
+		t.Fatalf("%v should be valid but is not: %v", valid, err)
+	}
+}
+
+func TestValidateConst(t *testing.T) {
+	table := []struct {
+		RawSchema string
+		Doc       string
+		Valid     bool
+	}{
+		{`{"const": null}`, `null`, true},
+		{`{"const": null}`, `0`, false},
+		{`{"const": {"a": [1, "b"]}}`, `{"a": [1.0, "b"]}`, true},
+		{`{"const": {"a": [1, "b"]}}`, `{"a": ["b", 1]}`, false},
+		{`{"enum": [false, 0]}`, `0`, true},
+		{`{"enum": [false, 0]}`, `""`, false},
+	}
+	for _, ts := range table {
+		idx, err := Parse([]byte(ts.RawSchema))
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = idx.ValidateJSON("#", []byte(ts.Doc))
+		if ts.Valid && err != nil {
+			t.Fatalf("%v should be valid against %v but is not: %v", ts.Doc, ts.RawSchema, err)
+		}
+		if !ts.Valid && err == nil {
+			t.Fatalf("%v should be invalid against %v but is not", ts.Doc, ts.RawSchema)
+		}
+	}
+}
+
+func TestValidateDynamicRef(t *testing.T) {
+	loader := MapLoader{
+		"tree.json": []byte(`{
+			"$schema": "https://json-schema.org/draft/2020-12/schema",
+			"$dynamicAnchor": "node",
+			"type": "object",
+			"properties": {
+				"data": true,
+				"children": {"type": "array", "items": {"$dynamicRef": "#node"}}
+			}
+		}`),
+		"strict-tree.json": []byte(`{
+			"$schema": "https://json-schema.org/draft/2020-12/schema",
+			"$dynamicAnchor": "node",
+			"allOf": [{"$ref": "tree.json"}],
+			"unevaluatedProperties": false
+		}`),
+		"recursive-tree.json": []byte(`{
+			"$schema": "https://json-schema.org/draft/2019-09/schema",
+			"$recursiveAnchor": true,
+			"type": "object",
+			"properties": {
+				"children": {"type": "array", "items": {"$recursiveRef": "#"}}
+			}
+		}`),
+		"recursive-strict-tree.json": []byte(`{
+			"$schema": "https://json-schema.org/draft/2019-09/schema",
+			"$recursiveAnchor": true,
+			"allOf": [{"$ref": "recursive-tree.json"}],
+			"unevaluatedProperties": false
+		}`),
+	}
+
+	table := []struct {
+		URI   string
+		Doc   string
+		Valid bool
+	}{
+		{"tree.json", `{"children": [{"daat": 1}]}`, true},
+		{"strict-tree.json", `{"children": [{"data": 1}]}`, true},
+		{"strict-tree.json", `{"children": [{"daat": 1}]}`, false},
+		{"strict-tree.json", `{"children": [{"children": [{"daat": 1}]}]}`, false},
+		{"recursive-tree.json", `{"children": [{"daat": 1}]}`, true},
+		{"recursive-strict-tree.json", `{"children": [{"children": []}]}`, true},
+		{"recursive-strict-tree.json", `{"children": [{"daat": 1}]}`, false},
+	}
+	for _, ts := range table {
+		idx, err := ParseWithLoader(ts.URI, loader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = idx.ValidateJSON("#", []byte(ts.Doc))
+		if ts.Valid && err != nil {
+			t.Fatalf("%v should be valid against %v but is not: %v", ts.Doc, ts.URI, err)
+		}
+		if !ts.Valid && err == nil {
+			t.Fatalf("%v should be invalid against %v but is not", ts.Doc, ts.URI)
+		}
+	}
+}
+
+func TestValidateConcurrently(t *testing.T) {
+	idx, err := Parse([]byte(fixture.TestSchemaCompositionValidation))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	docs := map[string]bool{
+		`{"name": "Tom", "lives": 9}`:                    true,
+		`{"name": "Rex", "breed": "Beagle"}`:             true,
+		`{"name": "Rex", "breed": "Beagle", "lives": 9}`: false,
+		`{"name": "Rex"}`:                                false,
+	}
+	var wg sync.WaitGroup
+	errs := make(chan string, 100*len(docs))
+	for i := 0; i < 100; i++ {
+		for doc, valid := range docs {
+			wg.Add(1)
+			go func(doc string, valid bool) {
+				defer wg.Done()
+				err := idx.ValidateJSONWithOptions("#/definitions/pet", []byte(doc), ValidateOptions{AllErrors: true})
+				if valid != (err == nil) {
+					errs <- fmt.Sprintf("%v should be valid: %v, but validation returned %v", doc, valid, err)
+				}
+			}(doc, valid)
+		}
+	}
+	wg.Wait()
+	close(errs)
+	for msg := range errs {
+		t.Fatal(msg)
+	}
+}
@@ -0,0 +1,198 @@
+package jsonschema
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+)
+
+// SkipChildren is returned by the fn of Walk to skip the subschemas of the visited schema
+var SkipChildren = errors.New("jsonschema: skip children")
+
+// Walk calls fn for each schema of the index in document order, depth-first starting at the root of each document,
+// the document root # first followed by referenced documents sorted by URI. Aliases of $id and $anchor are not visited.
+//
+// Walk stops at the first error returned by fn and returns it, unless it is SkipChildren.
+func (idx *Index) Walk(fn func(ptr string, s *Schema) error) error {
+	var walk func(s *Schema) error
+	walk = func(s *Schema) error {
+		err := fn(s.Pointer, s)
+		if err == SkipChildren {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		for _, c := range s.Children() {
+			if err := walk(c); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for _, s := range idx.documents() {
+		if err := walk(s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Roots returns the definitions and $defs of the root of each document in document order,
+// i.e. the top-level definitions of the document root # followed by those of referenced documents sorted by URI
+func (idx *Index) Roots() []*Schema {
+	var roots []*Schema
+	for _, d := range idx.documents() {
+		ptr := Pointer(d.Pointer)
+		for _, c := range d.Children() {
+			if parent := Pointer(c.Pointer).Parent(); parent == ptr.Append("definitions") || parent == ptr.Append("$defs") {
+				roots = append(roots, c)
+			}
+		}
+	}
+	return roots
+}
+
+// Parent returns the schema containing the schema of a key, i.e. a JSON pointer or the URI of an $id or $anchor,
+// false for the root of a document or a key not in the index
+func (idx *Index) Parent(key string) (*Schema, bool) {
+	s, ok := idx.Get(key)
+	if !ok {
+		return nil, false
+	}
+	for ptr := Pointer(s.Pointer); ptr.Parent() != ptr; {
+		ptr = ptr.Parent()
+		if p, ok := idx.Get(string(ptr)); ok && p.Pointer == string(ptr) {
+			return p, true
+		}
+	}
+	return nil, false
+}
+
+// returns the roots of all documents of the index sorted by URI, the document root # first
+func (idx *Index) documents() []*Schema {
+	var ds []*Schema
+	idx.Range(func(k string, s *Schema) bool {
+		if k == s.Pointer && Pointer(k).Parent() == Pointer(k) {
+			ds = append(ds, s)
+		}
+		return true
+	})
+	return ds
+}
+
+// Children returns the subschemas directly below a schema in document order, e.g. its definitions,
+// properties and items. Subschemas of keywords not defined by the draft of the schema are omitted as they are not indexed.
+//
+// Schemas without positions, i.e. not parsed from a document, return their subschemas in the order of the keywords
+// of Schema with the names of definitions and properties sorted by alphabet.
+func (s *Schema) Children() []*Schema {
+	cs := s.children()
+	positioned := true
+	for _, c := range cs {
+		positioned = positioned && c.schema.Position.IsValid()
+	}
+	if positioned {
+		sort.SliceStable(cs, func(i, j int) bool {
+			return cs[i].schema.Position.Offset < cs[j].schema.Position.Offset
+		})
+	}
+
+	ss := make([]*Schema, 0, len(cs))
+	for _, c := range cs {
+		ss = append(ss, c.schema)
+	}
+	return ss
+}
+
+// a subschema below a schema at the path of tokens, e.g. properties and name
+type child struct {
+	tokens []string
+	schema *Schema
+}
+
+// returns the subschemas below a schema in the order of the keywords of Schema, names sorted by alphabet
+func (s *Schema) children() []child {
+	var cs []child
+	for _, c := range s.slots() {
+		if c.schema != nil {
+			cs = append(cs, c)
+		}
+	}
+	return cs
+}
+
+// returns the children of a schema including the nil entries of lists and maps of subschemas decoded from null
+func (s *Schema) slots() []child {
+	var cs []child
+	one := func(keyword string, sch *Schema) {
+		if sch != nil {
+			cs = append(cs, child{[]string{keyword}, sch})
+		}
+	}
+	list := func(keyword string, l []*Schema) {
+		for i, sch := range l {
+			cs = append(cs, child{[]string{keyword, strconv.Itoa(i)}, sch})
+		}
+	}
+	named := func(keyword string, m Schemas) {
+		for _, name := range sortedSchemaNames(m) {
+			cs = append(cs, child{[]string{keyword, name}, m[name]})
+		}
+	}
+
+	named("definitions", s.Definitions)
+	named("$defs", s.Defs)
+	named("properties", s.Properties)
+	one("items", s.Items)
+	if s.Draft != Draft202012 {
+		list("items", s.ItemsList)
+	}
+	if s.Draft.defines("additionalItems") {
+		one("additionalItems", s.AdditionalItems)
+	}
+	if s.Draft.defines("prefixItems") {
+		list("prefixItems", s.PrefixItems)
+	}
+	if s.Draft.defines("unevaluatedItems") {
+		one("unevaluatedItems", s.UnevaluatedItems)
+	}
+	if s.Draft.defines("unevaluatedProperties") {
+		one("unevaluatedProperties", s.UnevaluatedProperties)
+	}
+	named("patternProperties", s.PatternProperties)
+	one("additionalProperties", s.AdditionalProperties)
+	list("allOf", s.AllOf)
+	list("anyOf", s.AnyOf)
+	list("oneOf", s.OneOf)
+	one("not", s.Not)
+	one("contains", s.Contains)
+	one("propertyNames", s.PropertyNames)
+	one("if", s.If)
+	one("then", s.Then)
+	one("else", s.Else)
+	named("dependentSchemas", s.DependentSchemas)
+	named("dependencies", s.Dependencies)
+	return cs
+}
+
+// returns the pointer of the first null subschema below a schema at ptr, false if there is none
+func (s *Schema) nullSubschema(ptr Pointer) (Pointer, bool) {
+	if len(s.nulls) > 0 {
+		return ptr.Append(s.nulls[0]), true
+	}
+	for _, c := range s.slots() {
+		p := ptr
+		for _, t := range c.tokens {
+			p = p.Append(t)
+		}
+		if c.schema == nil {
+			return p, true
+		}
+		if n, ok := c.schema.nullSubschema(p); ok {
+			return n, true
+		}
+	}
+	return "", false
+}
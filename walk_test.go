@@ -0,0 +1,174 @@
+package jsonschema
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+const testSchemaWalk = `{
+	"$schema": "http://json-schema.org/draft-07/schema#",
+	"$id": "http://example.com/schema.json",
+	"definitions": {
+		"zoo": {
+			"type": "object",
+			"properties": {
+				"name": {"type": "string"},
+				"animals": {"type": "array", "items": {"$ref": "#/definitions/animal"}}
+			},
+			"prefixItems": [{"type": "string"}]
+		},
+		"animal": {"$id": "#pet", "type": "object", "properties": {"legs": {"type": "integer"}}}
+	}
+}`
+
+func TestWalk(t *testing.T) {
+	idx, err := Parse([]byte(testSchemaWalk))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ptrs []string
+	err = idx.Walk(func(ptr string, s *Schema) error {
+		ptrs = append(ptrs, ptr)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{
+		"#",
+		"#/definitions/zoo",
+		"#/definitions/zoo/properties/name",
+		"#/definitions/zoo/properties/animals",
+		"#/definitions/zoo/properties/animals/items",
+		"#/definitions/animal",
+		"#/definitions/animal/properties/legs",
+	}
+	if strings.Join(ptrs, " ") != strings.Join(expected, " ") {
+		t.Fatalf("walk should visit %v but visits %v", expected, ptrs)
+	}
+
+	ptrs = nil
+	err = idx.Walk(func(ptr string, s *Schema) error {
+		ptrs = append(ptrs, ptr)
+		if s.Type == "object" {
+			return SkipChildren
+		}
+		return nil
+	})
+	if err != nil || fmt.Sprint(ptrs) != "[# #/definitions/zoo #/definitions/animal]" {
+		t.Fatalf("walk should skip the children of objects but visits %v and returns %v", ptrs, err)
+	}
+
+	stop := errors.New("stop")
+	ptrs = nil
+	err = idx.Walk(func(ptr string, s *Schema) error {
+		ptrs = append(ptrs, ptr)
+		if ptr == "#/definitions/zoo/properties/name" {
+			return stop
+		}
+		return nil
+	})
+	if err != stop || len(ptrs) != 3 {
+		t.Fatalf("walk should stop at the first error but visits %v and returns %v", ptrs, err)
+	}
+}
+
+func TestWalkWithLoader(t *testing.T) {
+	loader := MapLoader{
+		"schema.json": []byte(`{"definitions": {"person": {"$ref": "common.json#/definitions/name"}}}`),
+		"common.json": []byte(`{"definitions": {"name": {"type": "string"}}}`),
+	}
+	idx, err := ParseWithLoader("schema.json", loader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ptrs []string
+	idx.Walk(func(ptr string, s *Schema) error {
+		ptrs = append(ptrs, ptr)
+		return nil
+	})
+	if fmt.Sprint(ptrs) != "[# #/definitions/person common.json# common.json#/definitions/name]" {
+		t.Fatalf("walk should visit the document root first followed by referenced documents but visits %v", ptrs)
+	}
+
+	var names []string
+	for _, s := range idx.Roots() {
+		names = append(names, s.Pointer)
+	}
+	if fmt.Sprint(names) != "[#/definitions/person common.json#/definitions/name]" {
+		t.Fatalf("roots should be the definitions of all documents but are %v", names)
+	}
+}
+
+func TestChildren(t *testing.T) {
+	idx, err := Parse([]byte(testSchemaWalk))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	table := map[string]string{
+		"#":                                    "[#/definitions/zoo #/definitions/animal]",
+		"#/definitions/zoo":                    "[#/definitions/zoo/properties/name #/definitions/zoo/properties/animals]",
+		"#/definitions/zoo/properties/animals": "[#/definitions/zoo/properties/animals/items]",
+		"#/definitions/zoo/properties/name":    "[]",
+	}
+	for ptr, expected := range table {
+		var children []string
+		for _, c := range idx.schemas[ptr].Children() {
+			children = append(children, c.Pointer)
+		}
+		if fmt.Sprint(children) != expected {
+			t.Fatalf("children of %v should be %v but are %v", ptr, expected, children)
+		}
+	}
+
+	// schemas without positions are ordered by keyword and name
+	s := &Schema{
+		Properties:  Schemas{"b": &Schema{Title: "b"}, "a": &Schema{Title: "a"}},
+		Definitions: Schemas{"c": &Schema{Title: "c"}},
+		Not:         &Schema{Title: "not"},
+	}
+	var titles []string
+	for _, c := range s.Children() {
+		titles = append(titles, c.Title)
+	}
+	if fmt.Sprint(titles) != "[c a b not]" {
+		t.Fatalf("children without positions should be ordered by keyword and name but are %v", titles)
+	}
+}
+
+func TestParent(t *testing.T) {
+	idx, err := Parse([]byte(testSchemaWalk))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	table := []struct {
+		Key    string
+		Parent string
+	}{
+		{"#/definitions/zoo/properties/animals/items", "#/definitions/zoo/properties/animals"},
+		{"#/definitions/zoo/properties/name", "#/definitions/zoo"},
+		{"#/definitions/zoo", "#"},
+		{"#pet", "#"},
+		{"#", ""},
+		{"#/definitions/zoo/prefixItems/0", ""},
+		{"#/definitions/missing", ""},
+	}
+	for _, e := range table {
+		p, ok := idx.Parent(e.Key)
+		if e.Parent == "" {
+			if ok {
+				t.Fatalf("%v should have no parent but has %v", e.Key, p.Pointer)
+			}
+			continue
+		}
+		if !ok || p.Pointer != e.Parent {
+			t.Fatalf("parent of %v should be %v but is %v", e.Key, e.Parent, p)
+		}
+	}
+}